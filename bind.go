@@ -0,0 +1,400 @@
+package rou
+
+import (
+	"encoding"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Max size of request body which is read by Context.Bind if MaxBodySize of the router is not set
+const DefaultMaxBodySize = 10 << 20
+
+// Max size of multipart form which is stored in memory, other parts are stored in temporary files
+const multipartMemory = 32 << 20
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	fileHeaderType      = reflect.TypeOf((*multipart.FileHeader)(nil))
+)
+
+// Returns max size of request body, it is negative if size is not limited
+func (c Context) maxBodySize() int64 {
+	if c.router == nil || c.router.MaxBodySize == 0 {
+		return DefaultMaxBodySize
+	}
+	return c.router.MaxBodySize
+}
+
+func (c Context) disallowUnknownFields() bool {
+	return c.router != nil && c.router.DisallowUnknownFields
+}
+
+// Decodes body of request to dst by Content-Type: JSON, XML, urlencoded or multipart form.
+//
+// Fields of form are bound by "form" tag, files of multipart form are bound to fields of type
+// *multipart.FileHeader or []*multipart.FileHeader. Size of body is limited by MaxBodySize of the router,
+// unknown fields of JSON and forms are rejected if DisallowUnknownFields of the router is set.
+//
+// Decoded value is validated by "validate" tags with Validator of the router.
+// Returns HTTPError with status 400 and MessageBodyIsNotValid if body can not be decoded or is not valid,
+// 413 if body is too large and 415 if Content-Type is not supported.
+func (c Context) Bind(dst any) error {
+	tag, err := c.bindBody(dst)
+	if err != nil {
+		return err
+	}
+	return c.validate(dst, tag, MessageBodyIsNotValid)
+}
+
+// Decodes body of request to dst without validation, returns tag which names fields of decoded format
+func (c Context) bindBody(dst any) (string, error) {
+	request := c.Request()
+	if request.Body == nil || request.Body == http.NoBody {
+		return "", NewHTTPError(http.StatusBadRequest, MessageBodyIsNotValid).Wrap(io.EOF)
+	}
+	if limit := c.maxBodySize(); limit > 0 {
+		request.Body = http.MaxBytesReader(c.ResponseWriter(), request.Body, limit)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	if err != nil {
+		mediaType = ""
+	}
+
+	var tag string
+	switch {
+	case isJSONMediaType(mediaType):
+		tag, err = "json", c.bindJSON(dst)
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		tag, err = "xml", xml.NewDecoder(request.Body).Decode(dst)
+	case mediaType == "application/x-www-form-urlencoded":
+		tag, err = "form", c.bindForm(dst, false)
+	case mediaType == "multipart/form-data":
+		tag, err = "form", c.bindForm(dst, true)
+	default:
+		return "", NewHTTPError(http.StatusUnsupportedMediaType, MessageUnsupportedMediaType)
+	}
+	return tag, bodyError(err)
+}
+
+// Converts error of decoding body to HTTPError
+func bodyError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return err
+	}
+	// http.MaxBytesError is not available in Go 1.18
+	if strings.Contains(err.Error(), "http: request body too large") {
+		return NewHTTPError(http.StatusRequestEntityTooLarge, MessageBodyIsTooLarge).Wrap(err)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return NewHTTPError(http.StatusBadRequest, MessageBodyIsNotValid).Wrap(err).WithDetails([]SchemaViolation{{
+			In:      "body",
+			Path:    "/" + strings.ReplaceAll(typeErr.Field, ".", "/"),
+			Message: fmt.Sprintf("expected %s, got %s", typeErr.Type, typeErr.Value),
+		}})
+	}
+	return NewHTTPError(http.StatusBadRequest, MessageBodyIsNotValid).Wrap(err)
+}
+
+func (c Context) bindJSON(dst any) error {
+	decoder := json.NewDecoder(c.Request().Body)
+	if c.disallowUnknownFields() {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(dst); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return errors.New("body has data after JSON value")
+	}
+	return nil
+}
+
+func (c Context) bindForm(dst any, multipartForm bool) error {
+	request := c.Request()
+	var err error
+	if multipartForm {
+		err = request.ParseMultipartForm(multipartMemory)
+	} else {
+		err = request.ParseForm()
+	}
+	if err != nil {
+		return err
+	}
+
+	binder := valuesBinder{tag: "form", in: "body", values: request.PostForm, strict: c.disallowUnknownFields()}
+	if request.MultipartForm != nil {
+		binder.values = request.MultipartForm.Value
+		binder.files = request.MultipartForm.File
+	}
+	return binder.bind(dst, MessageBodyIsNotValid)
+}
+
+// Decodes query params of request to fields of dst with "query" tag
+//
+// Returns HTTPError with status 400 and MessageRequestIsNotValid if some values can not be converted or are not valid.
+func (c Context) BindQuery(dst any) error {
+	if err := c.bindQuery(dst); err != nil {
+		return err
+	}
+	return c.validate(dst, "query", MessageRequestIsNotValid)
+}
+
+func (c Context) bindQuery(dst any) error {
+	binder := valuesBinder{tag: "query", in: "query", values: c.Request().URL.Query()}
+	return binder.bind(dst, MessageRequestIsNotValid)
+}
+
+// Decodes route params to fields of dst with "param" tag
+//
+// Returns HTTPError with status 400 and MessageRequestIsNotValid if some values can not be converted or are not valid.
+func (c Context) BindParams(dst any) error {
+	if err := c.bindParams(dst); err != nil {
+		return err
+	}
+	return c.validate(dst, "param", MessageRequestIsNotValid)
+}
+
+func (c Context) bindParams(dst any) error {
+	binder := valuesBinder{tag: "param", in: "path", lookup: func(name string) ([]string, bool) {
+		if !c.RouterParams().Has(name) {
+			return nil, false
+		}
+		return []string{c.RouterParams().Get(name)}, true
+	}}
+	return binder.bind(dst, MessageRequestIsNotValid)
+}
+
+// Decodes headers of request to fields of dst with "header" tag, names of headers are case-insensitive
+//
+// Returns HTTPError with status 400 and MessageRequestIsNotValid if some values can not be converted or are not valid.
+func (c Context) BindHeader(dst any) error {
+	if err := c.bindHeader(dst); err != nil {
+		return err
+	}
+	return c.validate(dst, "header", MessageRequestIsNotValid)
+}
+
+func (c Context) bindHeader(dst any) error {
+	header := c.Request().Header
+	binder := valuesBinder{tag: "header", in: "header", lookup: func(name string) ([]string, bool) {
+		values, ok := header[textproto.CanonicalMIMEHeaderKey(name)]
+		return values, ok
+	}}
+	return binder.bind(dst, MessageRequestIsNotValid)
+}
+
+// Sets values to fields of struct by names from the tag
+type valuesBinder struct {
+	tag string
+	// Part of request which is reported in SchemaViolation
+	in     string
+	values map[string][]string
+	files  map[string][]*multipart.FileHeader
+	lookup func(name string) ([]string, bool)
+	// Values without fields are reported as errors
+	strict     bool
+	used       map[string]bool
+	violations []SchemaViolation
+}
+
+func (b *valuesBinder) get(name string) ([]string, bool) {
+	if b.lookup != nil {
+		return b.lookup(name)
+	}
+	values, ok := b.values[name]
+	return values, ok
+}
+
+func (b *valuesBinder) bind(dst any, message string) error {
+	value := reflect.ValueOf(dst)
+	if value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("rou: binding destination should be pointer to struct, got %T", dst)
+	}
+
+	b.used = make(map[string]bool)
+	b.bindStruct(value.Elem())
+
+	if b.strict {
+		var unknown []string
+		for name := range b.values {
+			if !b.used[name] {
+				unknown = append(unknown, name)
+			}
+		}
+		for name := range b.files {
+			if !b.used[name] {
+				unknown = append(unknown, name)
+			}
+		}
+		sort.Strings(unknown)
+		for _, name := range unknown {
+			b.violations = append(b.violations, SchemaViolation{In: b.in, Path: name, Message: "unknown field"})
+		}
+	}
+
+	if len(b.violations) > 0 {
+		return NewHTTPError(http.StatusBadRequest, message).WithDetails(b.violations)
+	}
+	return nil
+}
+
+func (b *valuesBinder) bindStruct(value reflect.Value) {
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldValue := value.Field(i)
+		name := strings.Split(field.Tag.Get(b.tag), ",")[0]
+
+		if name == "" && field.Anonymous {
+			if field.Type.Kind() == reflect.Struct {
+				b.bindStruct(fieldValue)
+			} else if field.Type.Kind() == reflect.Pointer && field.Type.Elem().Kind() == reflect.Struct && field.IsExported() {
+				if fieldValue.IsNil() {
+					fieldValue.Set(reflect.New(field.Type.Elem()))
+				}
+				b.bindStruct(fieldValue.Elem())
+			}
+			continue
+		}
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+
+		if field.Type == fileHeaderType || field.Type == reflect.SliceOf(fileHeaderType) {
+			files, ok := b.files[name]
+			if !ok || len(files) == 0 {
+				continue
+			}
+			b.used[name] = true
+			if field.Type == fileHeaderType {
+				fieldValue.Set(reflect.ValueOf(files[0]))
+			} else {
+				fieldValue.Set(reflect.ValueOf(files))
+			}
+			continue
+		}
+
+		values, ok := b.get(name)
+		if !ok {
+			continue
+		}
+		b.used[name] = true
+		if err := setValues(fieldValue, values); err != nil {
+			b.violations = append(b.violations, SchemaViolation{In: b.in, Path: name, Message: err.Error()})
+		}
+	}
+}
+
+// Sets values to the field, slices and pointers to slices get all values and other types the first one
+func setValues(field reflect.Value, values []string) error {
+	if field.Kind() == reflect.Pointer && isValuesSlice(field.Type().Elem()) {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+		return setValues(field.Elem(), values)
+	}
+	if isValuesSlice(field.Type()) {
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := setValue(slice.Index(i), value); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return setValue(field, values[0])
+}
+
+// Returns TRUE if the type is slice which gets all values, []byte and slices implementing encoding.TextUnmarshaler get one value
+func isValuesSlice(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8 && !implementsTextUnmarshaler(t)
+}
+
+func implementsTextUnmarshaler(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// Converts string to the type of the field and sets it
+func setValue(field reflect.Value, value string) error {
+	if field.Kind() == reflect.Pointer {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+		return setValue(field.Elem(), value)
+	}
+
+	if implementsTextUnmarshaler(field.Type()) {
+		if err := field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("value %q is not valid: %v", value, err)
+		}
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("expected boolean, got %q", value)
+		}
+		field.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if field.Type() == reflect.TypeOf(time.Duration(0)) {
+			parsed, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("expected duration, got %q", value)
+			}
+			field.SetInt(int64(parsed))
+			return nil
+		}
+		parsed, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected integer, got %q", value)
+		}
+		field.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected unsigned integer, got %q", value)
+		}
+		field.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected number, got %q", value)
+		}
+		field.SetFloat(parsed)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("unsupported type %s", field.Type())
+		}
+		field.SetBytes([]byte(value))
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+package rou
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type bindUser struct {
+	Name  string   `json:"name" xml:"name" form:"name"`
+	Age   int      `json:"age" xml:"age" form:"age"`
+	Tags  []string `json:"tags" xml:"tag" form:"tag"`
+	Admin *bool    `json:"admin" xml:"admin" form:"admin"`
+}
+
+func newBindContext(router *SimpleRouter, r *http.Request) *Context {
+	return router.createContext(httptest.NewRecorder(), r)
+}
+
+func bindRequest(contentType string, body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+	r.Header.Set("Content-Type", contentType)
+	return r
+}
+
+func TestBind(t *testing.T) {
+	admin := true
+	want := bindUser{Name: "John", Age: 30, Tags: []string{"a", "b"}, Admin: &admin}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{"json", "application/json; charset=utf-8", `{"name":"John","age":30,"tags":["a","b"],"admin":true}`},
+		{"json suffix", "application/merge-patch+json", `{"name":"John","age":30,"tags":["a","b"],"admin":true}`},
+		{"xml", "application/xml", `<user><name>John</name><age>30</age><tag>a</tag><tag>b</tag><admin>true</admin></user>`},
+		{"form", "application/x-www-form-urlencoded", "name=John&age=30&tag=a&tag=b&admin=true"},
+	}
+
+	router := NewRouter()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got bindUser
+			if err := newBindContext(router, bindRequest(test.contentType, test.body)).Bind(&got); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Got - %+v, want - %+v", got, want)
+			}
+		})
+	}
+
+	t.Run("multipart", func(t *testing.T) {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		writer.WriteField("name", "John")
+		writer.WriteField("age", "30")
+		file, _ := writer.CreateFormFile("avatar", "avatar.png")
+		file.Write([]byte("image"))
+		writer.Close()
+
+		var got struct {
+			Name   string                `form:"name"`
+			Age    int                   `form:"age"`
+			Avatar *multipart.FileHeader `form:"avatar"`
+		}
+		err := newBindContext(router, bindRequest(writer.FormDataContentType(), body.String())).Bind(&got)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Name != "John" || got.Age != 30 || got.Avatar == nil || got.Avatar.Filename != "avatar.png" {
+			t.Errorf("Got - %+v", got)
+		}
+	})
+}
+
+func TestBindErrors(t *testing.T) {
+	strict := NewRouter()
+	strict.DisallowUnknownFields = true
+	limited := NewRouter()
+	limited.MaxBodySize = 8
+
+	tests := []struct {
+		name        string
+		router      *SimpleRouter
+		contentType string
+		body        string
+		status      int
+		message     string
+		details     []SchemaViolation
+	}{
+		{
+			name: "invalid json", router: NewRouter(), contentType: "application/json", body: `{"name":`,
+			status: http.StatusBadRequest, message: MessageBodyIsNotValid,
+		},
+		{
+			name: "wrong type", router: NewRouter(), contentType: "application/json", body: `{"age":"ten"}`,
+			status: http.StatusBadRequest, message: MessageBodyIsNotValid,
+			details: []SchemaViolation{{In: "body", Path: "/age", Message: "expected int, got string"}},
+		},
+		{
+			name: "empty body", router: NewRouter(), contentType: "application/json", body: "",
+			status: http.StatusBadRequest, message: MessageBodyIsNotValid,
+		},
+		{
+			name: "unknown json field", router: strict, contentType: "application/json", body: `{"email":"a"}`,
+			status: http.StatusBadRequest, message: MessageBodyIsNotValid,
+		},
+		{
+			name: "unknown form field", router: strict, contentType: "application/x-www-form-urlencoded", body: "name=a&email=b",
+			status: http.StatusBadRequest, message: MessageBodyIsNotValid,
+			details: []SchemaViolation{{In: "body", Path: "email", Message: "unknown field"}},
+		},
+		{
+			name: "invalid form value", router: NewRouter(), contentType: "application/x-www-form-urlencoded", body: "age=ten",
+			status: http.StatusBadRequest, message: MessageBodyIsNotValid,
+			details: []SchemaViolation{{In: "body", Path: "age", Message: `expected integer, got "ten"`}},
+		},
+		{
+			name: "too large", router: limited, contentType: "application/json", body: `{"name":"John"}`,
+			status: http.StatusRequestEntityTooLarge, message: MessageBodyIsTooLarge,
+		},
+		{
+			name: "unsupported media type", router: NewRouter(), contentType: "text/plain", body: "John",
+			status: http.StatusUnsupportedMediaType, message: MessageUnsupportedMediaType,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var dst bindUser
+			err := newBindContext(test.router, bindRequest(test.contentType, test.body)).Bind(&dst)
+
+			var httpErr *HTTPError
+			if !errors.As(err, &httpErr) {
+				t.Fatalf("expected HTTPError, got %v", err)
+			}
+			if httpErr.Status != test.status || httpErr.Message != test.message {
+				t.Errorf("Got - %d %q, want - %d %q", httpErr.Status, httpErr.Message, test.status, test.message)
+			}
+			if test.details != nil && !reflect.DeepEqual(httpErr.Details, test.details) {
+				t.Errorf("Got details - %+v, want - %+v", httpErr.Details, test.details)
+			}
+		})
+	}
+}
+
+type bindPage struct {
+	Limit int `query:"limit"`
+}
+
+type bindFilter struct {
+	bindPage
+	Tags  []string   `query:"tag"`
+	Since *time.Time `query:"since"`
+	Exact bool       `query:"exact"`
+	Skip  string     `query:"-"`
+}
+
+func TestBindQueryParamsHeader(t *testing.T) {
+	router := NewRouter()
+
+	var filter bindFilter
+	var params struct {
+		ID   uint64 `param:"id"`
+		Path string `param:"path"`
+	}
+	var header struct {
+		RequestID string        `header:"x-request-id"`
+		Timeout   time.Duration `header:"X-Timeout"`
+		Missing   string        `header:"X-Missing"`
+	}
+	router.Get("/users/:id/*path", func(ctx *Context) {
+		if err := ctx.BindQuery(&filter); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if err := ctx.BindParams(&params); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if err := ctx.BindHeader(&header); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/users/10/docs/a.txt?limit=5&tag=a&tag=b&since=2024-01-02T03:04:05Z&exact=true&Skip=x", nil)
+	r.Header.Set("X-Request-Id", "abc")
+	r.Header.Set("X-Timeout", "5s")
+	router.ServeHTTP(httptest.NewRecorder(), r)
+
+	since := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	wantFilter := bindFilter{bindPage: bindPage{Limit: 5}, Tags: []string{"a", "b"}, Since: &since, Exact: true}
+	if !reflect.DeepEqual(filter, wantFilter) {
+		t.Errorf("Got query - %+v, want - %+v", filter, wantFilter)
+	}
+	if params.ID != 10 || params.Path != "docs/a.txt" {
+		t.Errorf("Got params - %+v", params)
+	}
+	if header.RequestID != "abc" || header.Timeout != 5*time.Second || header.Missing != "" {
+		t.Errorf("Got header - %+v", header)
+	}
+
+	t.Run("invalid values", func(t *testing.T) {
+		ctx := newBindContext(router, httptest.NewRequest(http.MethodGet, "/?limit=ten&exact=maybe", nil))
+		err := ctx.BindQuery(&bindFilter{})
+
+		var httpErr *HTTPError
+		if !errors.As(err, &httpErr) || httpErr.Status != http.StatusBadRequest || httpErr.Message != MessageRequestIsNotValid {
+			t.Fatalf("expected HTTPError with status 400, got %v", err)
+		}
+		want := []SchemaViolation{
+			{In: "query", Path: "limit", Message: `expected integer, got "ten"`},
+			{In: "query", Path: "exact", Message: `expected boolean, got "maybe"`},
+		}
+		if !reflect.DeepEqual(httpErr.Details, want) {
+			t.Errorf("Got details - %+v, want - %+v", httpErr.Details, want)
+		}
+	})
+
+	t.Run("pointer to slice", func(t *testing.T) {
+		var query struct {
+			IDs  *[]int  `query:"ids"`
+			Data *[]byte `query:"data"`
+		}
+		ctx := newBindContext(router, httptest.NewRequest(http.MethodGet, "/?ids=1&ids=2&data=abc", nil))
+		if err := ctx.BindQuery(&query); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if query.IDs == nil || !reflect.DeepEqual(*query.IDs, []int{1, 2}) || query.Data == nil || string(*query.Data) != "abc" {
+			t.Errorf("Got query - %v %v", query.IDs, query.Data)
+		}
+	})
+
+	t.Run("unsupported slice", func(t *testing.T) {
+		var query struct {
+			Matrix [][]int `query:"matrix"`
+		}
+		ctx := newBindContext(router, httptest.NewRequest(http.MethodGet, "/?matrix=1", nil))
+		err := ctx.BindQuery(&query)
+
+		var httpErr *HTTPError
+		want := []SchemaViolation{{In: "query", Path: "matrix", Message: "unsupported type []int"}}
+		if !errors.As(err, &httpErr) || !reflect.DeepEqual(httpErr.Details, want) {
+			t.Errorf("Got - %v, want - %+v", err, want)
+		}
+	})
+
+	t.Run("invalid destination", func(t *testing.T) {
+		ctx := newBindContext(router, httptest.NewRequest(http.MethodGet, "/", nil))
+		if err := ctx.BindQuery(bindFilter{}); err == nil {
+			t.Error("expected error for non-pointer destination")
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package rou
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Checks value of route param, the route is matched only if it returns TRUE
+type Constraint func(value string) bool
+
+const (
+	ConstraintInt   = "int"
+	ConstraintUint  = "uint"
+	ConstraintUUID  = "uuid"
+	ConstraintAlpha = "alpha"
+	ConstraintDate  = "date"
+)
+
+func isDigits(value string) bool {
+	if value == "" {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		if value[i] < '0' || value[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isInt(value string) bool {
+	if strings.HasPrefix(value, "-") || strings.HasPrefix(value, "+") {
+		value = value[1:]
+	}
+	return isDigits(value)
+}
+
+func isAlpha(value string) bool {
+	if value == "" {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		c := value[i] | 0x20
+		if c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// Checks that value is UUID in canonical form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx
+func isUUID(value string) bool {
+	if len(value) != 36 {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		switch i {
+		case 8, 13, 18, 23:
+			if value[i] != '-' {
+				return false
+			}
+		default:
+			if !isHex(value[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func isDate(value string) bool {
+	_, err := time.Parse("2006-01-02", value)
+	return err == nil
+}
+
+func defaultConstraints() map[string]Constraint {
+	return map[string]Constraint{
+		ConstraintInt:   isInt,
+		ConstraintUint:  isDigits,
+		ConstraintUUID:  isUUID,
+		ConstraintAlpha: isAlpha,
+		ConstraintDate:  isDate,
+	}
+}
+
+// Splits param segment ":id<int>" into name and constraint
+func parseParam(segment string) (name string, constraint string) {
+	name = segment[1:]
+	if start := strings.IndexByte(name, '<'); start >= 0 && strings.HasSuffix(name, ">") {
+		return name[:start], name[start+1 : len(name)-1]
+	}
+	return name, ""
+}
+
+// Returns registered constraint by name or compiles the expression as regular expression
+// which should match the whole value of param
+func resolveConstraint(constraints map[string]Constraint, expression string) (Constraint, error) {
+	if expression == "" {
+		return nil, nil
+	}
+	if constraint, ok := constraints[expression]; ok {
+		return constraint, nil
+	}
+	re, err := regexp.Compile("^(?:" + expression + ")$")
+	if err != nil {
+		return nil, err
+	}
+	return re.MatchString, nil
+}
+
+// Returns TRUE if constraint of param at the same position should be checked before the other one:
+// named constraints, built-in or registered, are checked before regular expressions, then they are ordered lexically
+func constraintBefore(constraints map[string]Constraint, expression, other string) bool {
+	_, named := constraints[expression]
+	_, otherNamed := constraints[other]
+	if named != otherNamed {
+		return named
+	}
+	return expression < other
+}
+
+// Registers named constraint which can be used in routes like "/users/:name<name>".
+//
+// Constraint should be registered before routes which use it. Registered constraint replaces built-in one with the same name.
+// Every SimpleRouter has its own constraints, built-in ones are "int", "uint", "uuid", "alpha" and "date" (2006-01-02).
+func (sr SimpleRouter) RegisterConstraint(name string, constraint Constraint) {
+	sr.Routes.constraints[name] = constraint
+}
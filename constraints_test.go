@@ -0,0 +1,187 @@
+package rou
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConstraints(t *testing.T) {
+	tests := []struct {
+		constraint string
+		valid      []string
+		invalid    []string
+	}{
+		{constraint: ConstraintInt, valid: []string{"10", "-10", "+7", "0"}, invalid: []string{"", "-", "1.5", "ten"}},
+		{constraint: ConstraintUint, valid: []string{"10", "0"}, invalid: []string{"-10", "+7", "1e3"}},
+		{constraint: ConstraintUUID, valid: []string{"123e4567-e89b-12d3-a456-426614174000"}, invalid: []string{"123e4567e89b12d3a456426614174000", "123e4567-e89b-12d3-a456-42661417400z"}},
+		{constraint: ConstraintAlpha, valid: []string{"abc", "ABC"}, invalid: []string{"ab1", "a-b", ""}},
+		{constraint: ConstraintDate, valid: []string{"2022-08-03"}, invalid: []string{"2022-13-03", "03.08.2022"}},
+		{constraint: "[a-z-]+", valid: []string{"hello-world"}, invalid: []string{"Hello", "hello world!"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.constraint, func(t *testing.T) {
+			constraint, err := resolveConstraint(defaultConstraints(), test.constraint)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, value := range test.valid {
+				if !constraint(value) {
+					t.Errorf("value %q should be valid", value)
+				}
+			}
+			for _, value := range test.invalid {
+				if constraint(value) {
+					t.Errorf("value %q should not be valid", value)
+				}
+			}
+		})
+	}
+}
+
+func TestRoutesWithConstraints(t *testing.T) {
+	writeRoute := func(route string) func(*Context) {
+		return func(ctx *Context) {
+			io.WriteString(ctx.ResponseWriter(), route+" "+ctx.RouterParams().Get("id"))
+		}
+	}
+
+	tests := []struct {
+		name        string
+		routes      []string
+		requestPath string
+		want        string
+		status      int
+	}{
+		{
+			name:        "value matches constraint",
+			routes:      []string{"/users/:id<int>"},
+			requestPath: "/users/10",
+			want:        "/users/:id<int> 10",
+			status:      http.StatusOK,
+		},
+		{
+			name:        "value does not match constraint",
+			routes:      []string{"/users/:id<int>"},
+			requestPath: "/users/melony",
+			status:      http.StatusNotFound,
+		},
+		{
+			name:        "falls through to param without constraint",
+			routes:      []string{"/users/:id", "/users/:id<int>"},
+			requestPath: "/users/melony",
+			want:        "/users/:id melony",
+			status:      http.StatusOK,
+		},
+		{
+			name:        "param with constraint beats param without it",
+			routes:      []string{"/users/:id", "/users/:id<int>"},
+			requestPath: "/users/10",
+			want:        "/users/:id<int> 10",
+			status:      http.StatusOK,
+		},
+		{
+			name:        "falls through to other constraint",
+			routes:      []string{"/users/:id<uuid>", "/users/:id<int>"},
+			requestPath: "/users/10",
+			want:        "/users/:id<int> 10",
+			status:      http.StatusOK,
+		},
+		{
+			name:        "named constraint beats regular expression registered first",
+			routes:      []string{"/users/:id<[0-9a-z]+>", "/users/:id<int>"},
+			requestPath: "/users/42",
+			want:        "/users/:id<int> 42",
+			status:      http.StatusOK,
+		},
+		{
+			name:        "named constraint beats regular expression registered last",
+			routes:      []string{"/users/:id<int>", "/users/:id<[0-9a-z]+>"},
+			requestPath: "/users/42",
+			want:        "/users/:id<int> 42",
+			status:      http.StatusOK,
+		},
+		{
+			name:        "overlapping regular expressions in lexical order",
+			routes:      []string{"/users/:id<[a-z]+>", "/users/:id<[0-9a-z]+>"},
+			requestPath: "/users/abc",
+			want:        "/users/:id<[0-9a-z]+> abc",
+			status:      http.StatusOK,
+		},
+		{
+			name:        "falls through to catch-all",
+			routes:      []string{"/users/:id<int>", "/users/*rest"},
+			requestPath: "/users/melony",
+			want:        "/users/*rest ",
+			status:      http.StatusOK,
+		},
+		{
+			name:        "regular expression",
+			routes:      []string{"/posts/:id<[a-z-]+>"},
+			requestPath: "/posts/hello-world",
+			want:        "/posts/:id<[a-z-]+> hello-world",
+			status:      http.StatusOK,
+		},
+		{
+			name:        "regular expression should match whole value",
+			routes:      []string{"/posts/:id<[a-z-]+>"},
+			requestPath: "/posts/hello-world-2022",
+			status:      http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := NewRouter()
+			for _, route := range test.routes {
+				router.Get(route, writeRoute(route))
+			}
+			if err := router.Validate(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			newServer := httptest.NewServer(router)
+			defer newServer.Close()
+			res, _ := http.Get(newServer.URL + test.requestPath)
+			bytesRes, _ := io.ReadAll(res.Body)
+
+			if res.StatusCode != test.status {
+				t.Errorf("expected status %d, got %d", test.status, res.StatusCode)
+			}
+			if test.status == http.StatusOK && string(bytesRes) != test.want {
+				t.Errorf("expected %q, got %q", test.want, string(bytesRes))
+			}
+		})
+	}
+
+	t.Run("custom constraint", func(t *testing.T) {
+		router := NewRouter()
+		router.RegisterConstraint("even", func(value string) bool {
+			return isDigits(value) && (value[len(value)-1]-'0')%2 == 0
+		})
+		router.Get("/numbers/:id<even>", writeRoute("even"))
+
+		newServer := httptest.NewServer(router)
+		defer newServer.Close()
+
+		res, _ := http.Get(newServer.URL + "/numbers/12")
+		if res.StatusCode != http.StatusOK {
+			t.Errorf("expected status %d, got %d", http.StatusOK, res.StatusCode)
+		}
+		res, _ = http.Get(newServer.URL + "/numbers/13")
+		if res.StatusCode != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, res.StatusCode)
+		}
+	})
+
+	t.Run("invalid regular expression", func(t *testing.T) {
+		router := NewRouter()
+		router.Get("/users/:id<[a-z>", writeRoute("invalid"))
+
+		if router.Validate() == nil {
+			t.Error("expected error for invalid constraint")
+		}
+	})
+}
@@ -2,15 +2,18 @@ package rou
 
 import (
 	"encoding/json"
-	"io"
 	"net/http"
 	"net/url"
 )
 
 type Context struct {
 	responseWriter http.ResponseWriter
+	// Writer created by router, it is kept if responseWriter is replaced
+	response       *responseWriter
 	request        *http.Request
 	routeParams    Storage
+	rawRouteParams Storage
+	router         *SimpleRouter
 }
 
 type Storage interface {
@@ -23,11 +26,14 @@ type Storage interface {
 type ErrorObject struct {
 	Message string `json:"message"`
 	Code    int    `json:"code"`
+	Details any    `json:"details,omitempty"`
 }
 
+// Response of DefaultEnvelope
 type ResponseObject[T any] struct {
 	Error *ErrorObject `json:"error"`
 	Body  T            `json:"body"`
+	Meta  any          `json:"meta,omitempty"`
 }
 
 // Returns basic HTTP response writer
@@ -40,6 +46,16 @@ func (c Context) Request() *http.Request {
 	return c.request
 }
 
+// Replaces request which is passed to the next handlers, e.g. r.WithContext(...) in middleware
+func (c *Context) SetRequest(r *http.Request) {
+	c.request = r
+}
+
+// Replaces response writer which is used by the next handlers
+func (c *Context) SetResponseWriter(w http.ResponseWriter) {
+	c.responseWriter = w
+}
+
 // Returns query params of request
 func (c Context) Params() url.Values {
 	return c.request.URL.Query()
@@ -54,22 +70,49 @@ func (ctx *Context) SetContentType(contentType string) {
 //
 // If you have route "/user/:id/posts/:postId" and request URL path "/user/1/posts/45" RouterParams will have map with keys
 // took from route and values which it will take from request URL path `["id": "1", "postId": "45"]`
+//
+// Catch-all segment of route "/static/*filepath" stores the rest of request URL path with slashes,
+// for "/static/css/main.css" it will be `["filepath": "css/main.css"]`
 func (c Context) RouterParams() Storage {
 	return c.routeParams
 }
 
-func (c Context) ErrorJSONResponse(status int, message string) {
-	c.ResponseWriter().Header().Add("Content-Type", "application/json")
+// Returns values of route params as they are in escaped path of request, e.g. `["name": "a%2Fb"]` for "/files/a%2Fb"
+//
+// Raw values are stored only if UseRawPath of the router is set.
+func (c Context) RawRouterParams() Storage {
+	return c.rawRouteParams
+}
+
+// Encodes value before anything is written, so the response is not written if it returns error
+func (c Context) writeJSON(status int, value any) error {
+	jsonContent, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	c.ResponseWriter().Header().Set("Content-Type", "application/json")
 	c.ResponseWriter().WriteHeader(status)
-	responseMsg := ResponseObject[any]{Error: &ErrorObject{Message: message, Code: status}}
-	jsonContent, _ := json.Marshal(responseMsg)
-	io.WriteString(c.ResponseWriter(), string(jsonContent))
+	c.ResponseWriter().Write(jsonContent)
+	return nil
+}
+
+// Writes error with message and status as code in Envelope of the router
+func (c Context) ErrorJSONResponse(status int, message string) {
+	c.writeError(status, &ErrorObject{Message: message, Code: status})
 }
 
+// Writes body in Envelope of the router with status 200
 func (c Context) SuccessJSONResponse(body any) {
-	c.ResponseWriter().Header().Add("Content-Type", "application/json")
-	c.ResponseWriter().WriteHeader(http.StatusOK)
-	responseMsg := ResponseObject[any]{Body: body}
-	jsonContent, _ := json.Marshal(responseMsg)
-	io.WriteString(c.ResponseWriter(), string(jsonContent))
+	c.JSONResponse(http.StatusOK, body)
+}
+
+// Renders error by ErrorHandler of the router
+//
+// HTTPError is rendered with its status, any other error is rendered with status 500.
+func (c *Context) Error(err error) {
+	if c.router != nil && c.router.ErrorHandler != nil {
+		c.router.ErrorHandler(c, err)
+		return
+	}
+	DefaultErrorHandler(c, err)
 }
@@ -4,6 +4,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 )
 
@@ -57,3 +58,202 @@ func TestDynamicRoutes(t *testing.T) {
 		}
 	})
 }
+
+func TestCatchAllRoutes(t *testing.T) {
+	tests := []struct {
+		name        string
+		routes      []string
+		requestPath string
+		route       string
+		params      map[string]string
+	}{
+		{
+			name:        "captures the rest of the path with slashes",
+			routes:      []string{"/static/*filepath"},
+			requestPath: "/static/css/main.css",
+			route:       "/static/*filepath",
+			params:      map[string]string{"filepath": "css/main.css"},
+		},
+		{
+			name:        "catch-all after named param",
+			routes:      []string{"/proxy/:service/*rest"},
+			requestPath: "/proxy/users/api/v1/list",
+			route:       "/proxy/:service/*rest",
+			params:      map[string]string{"service": "users", "rest": "api/v1/list"},
+		},
+		{
+			name:        "static route has priority",
+			routes:      []string{"/files/*path", "/files/index"},
+			requestPath: "/files/index",
+			route:       "/files/index",
+			params:      map[string]string{},
+		},
+		{
+			name:        "named param has priority",
+			routes:      []string{"/files/*path", "/files/:name"},
+			requestPath: "/files/report.pdf",
+			route:       "/files/:name",
+			params:      map[string]string{"name": "report.pdf"},
+		},
+		{
+			name:        "catch-all is used when named param does not match the rest",
+			routes:      []string{"/files/*path", "/files/:name"},
+			requestPath: "/files/2022/report.pdf",
+			route:       "/files/*path",
+			params:      map[string]string{"path": "2022/report.pdf"},
+		},
+		{
+			name:        "catch-all does not match empty rest",
+			routes:      []string{"/files/*path"},
+			requestPath: "/files",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := NewRouter()
+
+			var gotRoute string
+			var gotParams map[string]string
+			for _, route := range test.routes {
+				route := route
+				router.Get(route, func(ctx *Context) {
+					gotRoute = route
+					gotParams = ctx.RouterParams().(*routerBuilder).value
+				})
+			}
+
+			newServer := httptest.NewServer(router)
+			defer newServer.Close()
+			res, _ := http.Get(newServer.URL + test.requestPath)
+
+			if test.route == "" {
+				if res.StatusCode != http.StatusNotFound {
+					t.Errorf("expected status %d, got %d", http.StatusNotFound, res.StatusCode)
+				}
+				return
+			}
+			if gotRoute != test.route {
+				t.Errorf("wrong route. Got - %q, want - %q", gotRoute, test.route)
+			}
+			if !reflect.DeepEqual(gotParams, test.params) {
+				t.Errorf("wrong params. Got - %v, want - %v", gotParams, test.params)
+			}
+		})
+	}
+}
+
+func TestRoutePriority(t *testing.T) {
+	tests := []struct {
+		name        string
+		routes      []string
+		requestPath string
+		route       string
+		params      map[string]string
+	}{
+		{
+			name:        "static segment beats named param",
+			routes:      []string{"/users/:id", "/users/me"},
+			requestPath: "/users/me",
+			route:       "/users/me",
+			params:      map[string]string{},
+		},
+		{
+			name:        "named param matches other values",
+			routes:      []string{"/users/:id", "/users/me"},
+			requestPath: "/users/10",
+			route:       "/users/:id",
+			params:      map[string]string{"id": "10"},
+		},
+		{
+			name:        "static segment with common prefix does not match part of segment",
+			routes:      []string{"/users/:id", "/users/me"},
+			requestPath: "/users/melony",
+			route:       "/users/:id",
+			params:      map[string]string{"id": "melony"},
+		},
+		{
+			name:        "named param beats catch-all",
+			routes:      []string{"/users/*rest", "/users/:id"},
+			requestPath: "/users/10",
+			route:       "/users/:id",
+			params:      map[string]string{"id": "10"},
+		},
+		{
+			name:        "static segment beats catch-all",
+			routes:      []string{"/users/*rest", "/users/me"},
+			requestPath: "/users/me",
+			route:       "/users/me",
+			params:      map[string]string{},
+		},
+		{
+			name:        "priority is evaluated segment by segment",
+			routes:      []string{"/users/:id/posts", "/users/me/:section"},
+			requestPath: "/users/me/posts",
+			route:       "/users/me/:section",
+			params:      map[string]string{"section": "posts"},
+		},
+		{
+			name:        "falls back to named param when static branch does not match",
+			routes:      []string{"/users/:id/posts", "/users/me/settings"},
+			requestPath: "/users/me/posts",
+			route:       "/users/:id/posts",
+			params:      map[string]string{"id": "me"},
+		},
+		{
+			name:        "falls back to catch-all when named param branch does not match",
+			routes:      []string{"/files/:dir/index", "/files/*path"},
+			requestPath: "/files/docs/readme",
+			route:       "/files/*path",
+			params:      map[string]string{"path": "docs/readme"},
+		},
+		{
+			name:        "different param names at the same position",
+			routes:      []string{"/users/:id/posts", "/users/:name/profile"},
+			requestPath: "/users/melony/profile",
+			route:       "/users/:name/profile",
+			params:      map[string]string{"name": "melony"},
+		},
+	}
+
+	for _, test := range tests {
+		orders := map[string][]string{
+			"registration order": test.routes,
+			"reversed order":     reversed(test.routes),
+		}
+		for order, routes := range orders {
+			t.Run(test.name+" in "+order, func(t *testing.T) {
+				router := NewRouter()
+
+				var gotRoute string
+				var gotParams map[string]string
+				for _, route := range routes {
+					route := route
+					router.Get(route, func(ctx *Context) {
+						gotRoute = route
+						gotParams = ctx.RouterParams().(*routerBuilder).value
+					})
+				}
+
+				newServer := httptest.NewServer(router)
+				defer newServer.Close()
+				http.Get(newServer.URL + test.requestPath)
+
+				if gotRoute != test.route {
+					t.Errorf("wrong route. Got - %q, want - %q", gotRoute, test.route)
+				}
+				if !reflect.DeepEqual(gotParams, test.params) {
+					t.Errorf("wrong params. Got - %v, want - %v", gotParams, test.params)
+				}
+			})
+		}
+	}
+}
+
+func reversed(routes []string) []string {
+	result := make([]string, 0, len(routes))
+	for i := len(routes) - 1; i >= 0; i-- {
+		result = append(result, routes[i])
+	}
+	return result
+}
@@ -0,0 +1,180 @@
+package rou
+
+import (
+	"net/http"
+	"reflect"
+)
+
+// Defines layout of JSON responses written by Context.JSONResponse, SuccessJSONResponse, ErrorJSONResponse,
+// DefaultErrorHandler and typed handlers
+//
+//	router.Envelope = rou.BareEnvelope{}
+type Envelope interface {
+	// Returns value which is written for success response with the body and meta, meta is nil if it is not given
+	Success(body any, meta any) any
+	// Returns value which is written for error response
+	Error(err *ErrorObject) any
+}
+
+// Implemented by Envelope which describes its layout in OpenAPI document,
+// responses of router with envelope which does not implement it are described by empty schema
+type EnvelopeSchema interface {
+	// Returns schema of success response with the body, errorObject is schema of ErrorObject
+	SuccessSchema(body *Schema, errorObject *Schema) *Schema
+	// Returns schema of error response, errorObject is schema of ErrorObject
+	ErrorSchema(errorObject *Schema) *Schema
+}
+
+// Writes ResponseObject: {"error": null, "body": ...} and {"error": {...}, "body": null}, meta is written in "meta" if it is given
+type DefaultEnvelope struct{}
+
+func (DefaultEnvelope) Success(body any, meta any) any {
+	return ResponseObject[any]{Body: body, Meta: meta}
+}
+
+func (DefaultEnvelope) Error(err *ErrorObject) any {
+	return ResponseObject[any]{Error: err}
+}
+
+func (DefaultEnvelope) SuccessSchema(body *Schema, errorObject *Schema) *Schema {
+	return &Schema{
+		Type: SchemaType{"object"},
+		Properties: map[string]*Schema{
+			"error": {AnyOf: []*Schema{errorObject, {Type: SchemaType{"null"}}}},
+			"body":  body,
+		},
+		Required: []string{"error", "body"},
+	}
+}
+
+func (e DefaultEnvelope) ErrorSchema(errorObject *Schema) *Schema {
+	return e.SuccessSchema(&Schema{Type: SchemaType{"null"}}, errorObject)
+}
+
+// Writes body as it is and ErrorObject for errors, meta is not written
+type BareEnvelope struct{}
+
+func (BareEnvelope) Success(body any, meta any) any {
+	return body
+}
+
+func (BareEnvelope) Error(err *ErrorObject) any {
+	return err
+}
+
+func (BareEnvelope) SuccessSchema(body *Schema, errorObject *Schema) *Schema {
+	return body
+}
+
+func (BareEnvelope) ErrorSchema(errorObject *Schema) *Schema {
+	return errorObject
+}
+
+// Writes {"data": ..., "meta": ...} for success responses and {"error": {...}} for errors, meta is omitted if it is nil
+type DataEnvelope struct{}
+
+type dataResponse struct {
+	Data any `json:"data"`
+	Meta any `json:"meta,omitempty"`
+}
+
+type errorResponse struct {
+	Error *ErrorObject `json:"error"`
+}
+
+func (DataEnvelope) Success(body any, meta any) any {
+	return dataResponse{Data: body, Meta: meta}
+}
+
+func (DataEnvelope) Error(err *ErrorObject) any {
+	return errorResponse{Error: err}
+}
+
+func (DataEnvelope) SuccessSchema(body *Schema, errorObject *Schema) *Schema {
+	return &Schema{
+		Type:       SchemaType{"object"},
+		Properties: map[string]*Schema{"data": body, "meta": {}},
+		Required:   []string{"data"},
+	}
+}
+
+func (DataEnvelope) ErrorSchema(errorObject *Schema) *Schema {
+	return &Schema{
+		Type:       SchemaType{"object"},
+		Properties: map[string]*Schema{"error": errorObject},
+		Required:   []string{"error"},
+	}
+}
+
+// Returns Envelope of the router or DefaultEnvelope
+func (c Context) envelope() Envelope {
+	if c.router != nil && c.router.Envelope != nil {
+		return c.router.Envelope
+	}
+	return DefaultEnvelope{}
+}
+
+// Writes body with meta in Envelope of the router, response with status 204 is written without body.
+// Error of encoding is rendered by ErrorHandler as HTTPError with status 500 which wraps it.
+func (c *Context) JSONResponseWithMeta(status int, body any, meta any) {
+	if status == http.StatusNoContent {
+		c.ResponseWriter().WriteHeader(status)
+		return
+	}
+	if err := c.writeJSON(status, c.envelope().Success(body, meta)); err != nil {
+		c.Error(NewHTTPError(http.StatusInternalServerError, MessageInternalServerError).Wrap(err))
+	}
+}
+
+// Writes body in Envelope of the router with status
+func (c *Context) JSONResponse(status int, body any) {
+	c.JSONResponseWithMeta(status, body, nil)
+}
+
+// Writes body in Envelope of the router with status 201
+func (c *Context) Created(body any) {
+	c.JSONResponse(http.StatusCreated, body)
+}
+
+// Writes body in Envelope of the router with status 202
+func (c *Context) Accepted(body any) {
+	c.JSONResponse(http.StatusAccepted, body)
+}
+
+// Writes status 204 without body
+func (c *Context) NoContent() {
+	c.JSONResponse(http.StatusNoContent, nil)
+}
+
+// Writes error in Envelope of the router, ErrorObject with status 500 is written if the error can not be encoded
+// and plain text error with status 500 if the envelope can not be encoded either
+func (c Context) writeError(status int, err *ErrorObject) {
+	if c.writeJSON(status, c.envelope().Error(err)) == nil {
+		return
+	}
+	fallback := c.writeJSON(http.StatusInternalServerError, c.envelope().Error(&ErrorObject{
+		Message: MessageInternalServerError,
+		Code:    http.StatusInternalServerError,
+	}))
+	if fallback != nil {
+		http.Error(c.ResponseWriter(), MessageInternalServerError, http.StatusInternalServerError)
+	}
+}
+
+// Returns schema of success response with the body in Envelope of the router
+func (g *schemaGenerator) successSchema(body *Schema) *Schema {
+	described, ok := g.envelope.(EnvelopeSchema)
+	if !ok {
+		return &Schema{}
+	}
+	return described.SuccessSchema(body, g.schema(reflect.TypeOf(ErrorObject{})))
+}
+
+// Returns schema of error response in Envelope of the router
+func (g *schemaGenerator) errorSchema() *Schema {
+	described, ok := g.envelope.(EnvelopeSchema)
+	if !ok {
+		return &Schema{}
+	}
+	return described.ErrorSchema(g.schema(reflect.TypeOf(ErrorObject{})))
+}
@@ -0,0 +1,185 @@
+package rou
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func envelopeRouter(envelope Envelope) *SimpleRouter {
+	router := NewRouter()
+	router.Envelope = envelope
+	router.Get("/success", func(ctx *Context) {
+		ctx.SuccessJSONResponse(map[string]int{"id": 1})
+	})
+	router.Get("/meta", func(ctx *Context) {
+		ctx.JSONResponseWithMeta(http.StatusOK, []int{1, 2}, map[string]int{"total": 10})
+	})
+	router.Post("/created", func(ctx *Context) {
+		ctx.Created("done")
+	})
+	router.Post("/accepted", func(ctx *Context) {
+		ctx.Accepted(nil)
+	})
+	router.Delete("/deleted", func(ctx *Context) {
+		ctx.NoContent()
+	})
+	router.Get("/error", WithError(func(ctx *Context) error {
+		return NewHTTPError(http.StatusConflict, "Conflict")
+	}))
+	router.Get("/broken", func(ctx *Context) {
+		ctx.SuccessJSONResponse(func() {})
+	})
+	router.Get("/broken-error", WithError(func(ctx *Context) error {
+		return NewHTTPError(http.StatusBadRequest, "Bad").WithDetails(func() {})
+	}))
+	return router
+}
+
+func TestEnvelopes(t *testing.T) {
+	tests := []struct {
+		envelope Envelope
+		method   string
+		target   string
+		status   int
+		want     string
+	}{
+		{envelope: nil, method: http.MethodGet, target: "/success", status: http.StatusOK, want: `{"error":null,"body":{"id":1}}`},
+		{envelope: nil, method: http.MethodGet, target: "/meta", status: http.StatusOK, want: `{"error":null,"body":[1,2],"meta":{"total":10}}`},
+		{envelope: nil, method: http.MethodPost, target: "/created", status: http.StatusCreated, want: `{"error":null,"body":"done"}`},
+		{envelope: nil, method: http.MethodPost, target: "/accepted", status: http.StatusAccepted, want: `{"error":null,"body":null}`},
+		{envelope: nil, method: http.MethodDelete, target: "/deleted", status: http.StatusNoContent, want: ``},
+		{envelope: nil, method: http.MethodGet, target: "/error", status: http.StatusConflict, want: `{"error":{"message":"Conflict","code":409},"body":null}`},
+		{envelope: nil, method: http.MethodGet, target: "/broken", status: http.StatusInternalServerError, want: `{"error":{"message":"Internal server error","code":500},"body":null}`},
+		{envelope: nil, method: http.MethodGet, target: "/broken-error", status: http.StatusInternalServerError, want: `{"error":{"message":"Internal server error","code":500},"body":null}`},
+
+		{envelope: BareEnvelope{}, method: http.MethodGet, target: "/success", status: http.StatusOK, want: `{"id":1}`},
+		{envelope: BareEnvelope{}, method: http.MethodGet, target: "/meta", status: http.StatusOK, want: `[1,2]`},
+		{envelope: BareEnvelope{}, method: http.MethodPost, target: "/created", status: http.StatusCreated, want: `"done"`},
+		{envelope: BareEnvelope{}, method: http.MethodGet, target: "/error", status: http.StatusConflict, want: `{"message":"Conflict","code":409}`},
+
+		{envelope: DataEnvelope{}, method: http.MethodGet, target: "/success", status: http.StatusOK, want: `{"data":{"id":1}}`},
+		{envelope: DataEnvelope{}, method: http.MethodGet, target: "/meta", status: http.StatusOK, want: `{"data":[1,2],"meta":{"total":10}}`},
+		{envelope: DataEnvelope{}, method: http.MethodGet, target: "/error", status: http.StatusConflict, want: `{"error":{"message":"Conflict","code":409}}`},
+		{envelope: DataEnvelope{}, method: http.MethodGet, target: "/broken", status: http.StatusInternalServerError, want: `{"error":{"message":"Internal server error","code":500}}`},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%T %s", test.envelope, test.target), func(t *testing.T) {
+			w := httptest.NewRecorder()
+			envelopeRouter(test.envelope).ServeHTTP(w, httptest.NewRequest(test.method, test.target, nil))
+
+			if w.Code != test.status || w.Body.String() != test.want {
+				t.Errorf("Got - %d %s, want - %d %s", w.Code, w.Body.String(), test.status, test.want)
+			}
+		})
+	}
+}
+
+func TestEncodingErrorIsReported(t *testing.T) {
+	var reported error
+	router := NewRouter()
+	router.ErrorHandler = func(ctx *Context, err error) {
+		reported = err
+		DefaultErrorHandler(ctx, err)
+	}
+	router.Get("/", func(ctx *Context) {
+		ctx.SuccessJSONResponse(make(chan int))
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	var httpErr *HTTPError
+	if !errors.As(reported, &httpErr) || httpErr.Status != http.StatusInternalServerError || httpErr.Err == nil {
+		t.Errorf("expected HTTPError with status 500 which wraps error of encoding, got %v", reported)
+	}
+}
+
+type brokenEnvelope struct{}
+
+func (brokenEnvelope) Success(body any, meta any) any {
+	return body
+}
+
+func (brokenEnvelope) Error(err *ErrorObject) any {
+	return func() {}
+}
+
+func TestBrokenErrorEnvelope(t *testing.T) {
+	router := NewRouter()
+	router.Envelope = brokenEnvelope{}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	if want := MessageInternalServerError + "\n"; w.Code != http.StatusInternalServerError || w.Body.String() != want {
+		t.Errorf("Got - %d %q, want - %d %q", w.Code, w.Body.String(), http.StatusInternalServerError, want)
+	}
+	if contentType := w.Header().Get("Content-Type"); contentType != "text/plain; charset=utf-8" {
+		t.Errorf("Got content type - %q", contentType)
+	}
+}
+
+type listEnvelope struct{}
+
+func (listEnvelope) Success(body any, meta any) any {
+	return map[string]any{"items": body, "pagination": meta}
+}
+
+func (listEnvelope) Error(err *ErrorObject) any {
+	return map[string]any{"message": err.Message}
+}
+
+func TestEnvelopeOpenAPI(t *testing.T) {
+	tests := []struct {
+		name     string
+		envelope Envelope
+		success  func(schema *Schema) bool
+		failure  func(schema *Schema) bool
+	}{
+		{
+			name:     "default",
+			envelope: nil,
+			success: func(schema *Schema) bool {
+				return schema.Properties["body"].Ref == "#/components/schemas/typedResponse"
+			},
+			failure: func(schema *Schema) bool { return schema.Properties["body"].Type.Has("null") },
+		},
+		{
+			name:     "bare",
+			envelope: BareEnvelope{},
+			success:  func(schema *Schema) bool { return schema.Ref == "#/components/schemas/typedResponse" },
+			failure:  func(schema *Schema) bool { return schema.Ref == "#/components/schemas/ErrorObject" },
+		},
+		{
+			name:     "data",
+			envelope: DataEnvelope{},
+			success: func(schema *Schema) bool {
+				return schema.Properties["data"].Ref == "#/components/schemas/typedResponse"
+			},
+			failure: func(schema *Schema) bool { return schema.Properties["error"].Ref == "#/components/schemas/ErrorObject" },
+		},
+		{
+			name:     "custom without schema",
+			envelope: listEnvelope{},
+			success:  func(schema *Schema) bool { return schema.Type == nil && schema.Ref == "" },
+			failure:  func(schema *Schema) bool { return schema.Type == nil && schema.Ref == "" },
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := NewRouter()
+			router.Envelope = test.envelope
+			HandleJSON(router, MethodGet, "/users/:id", typedHandler)
+
+			responses := router.OpenAPI(OpenAPIInfo{}).Paths["/users/{id}"].Get.Responses
+			if success := responses["200"].Content["application/json"].Schema; !test.success(success) {
+				t.Errorf("Got success schema - %+v", success)
+			}
+			if failure := responses["default"].Content["application/json"].Schema; !test.failure(failure) {
+				t.Errorf("Got error schema - %+v", failure)
+			}
+		})
+	}
+}
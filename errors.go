@@ -0,0 +1,96 @@
+package rou
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Error with HTTP status which is rendered by ErrorHandler of SimpleRouter
+type HTTPError struct {
+	Status  int
+	Message string
+	// Code of error in response, equals to Status if it is not set
+	Code    int
+	Details any
+	// Cause of error, it is not rendered in response
+	Err error
+}
+
+// Creates HTTPError with status and message
+func NewHTTPError(status int, message string) *HTTPError {
+	return &HTTPError{Status: status, Message: message}
+}
+
+// Sets code of error which is rendered instead of status
+func (e *HTTPError) WithCode(code int) *HTTPError {
+	e.Code = code
+	return e
+}
+
+// Sets details of error which are rendered in response
+func (e *HTTPError) WithDetails(details any) *HTTPError {
+	e.Details = details
+	return e
+}
+
+// Sets cause of error
+func (e *HTTPError) Wrap(err error) *HTTPError {
+	e.Err = err
+	return e
+}
+
+func (e *HTTPError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// Returns HTTPError from the chain of err, HTTPError made of Problem from the chain of err
+// or HTTPError with status 500 which wraps err
+func AsHTTPError(err error) *HTTPError {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr
+	}
+	var problem *Problem
+	if errors.As(err, &problem) && problem.Status != 0 {
+		return NewHTTPError(problem.Status, problem.Title).Wrap(err)
+	}
+	return NewHTTPError(http.StatusInternalServerError, MessageInternalServerError).Wrap(err)
+}
+
+// Renders errors returned by handlers and built-in errors of router
+type ErrorHandler func(ctx *Context, err error)
+
+// Renders ErrorObject in Envelope of the router, errors which are not HTTPError are rendered with status 500
+func DefaultErrorHandler(ctx *Context, err error) {
+	httpErr := AsHTTPError(err)
+	code := httpErr.Code
+	if code == 0 {
+		code = httpErr.Status
+	}
+	ctx.writeError(httpErr.Status, &ErrorObject{Message: httpErr.Message, Code: code, Details: httpErr.Details})
+}
+
+// Converts handler which returns error to Handler, the error is rendered by ErrorHandler of SimpleRouter
+//
+//	router.Get("/users/:id", rou.WithError(func(ctx *rou.Context) error {
+//		user, err := findUser(ctx.RouterParams().Get("id"))
+//		if err != nil {
+//			return rou.NewHTTPError(http.StatusNotFound, "User not found").Wrap(err)
+//		}
+//		ctx.SuccessJSONResponse(user)
+//		return nil
+//	}))
+func WithError(handler func(*Context) error) Handler {
+	return wrapped(func(ctx *Context) {
+		if err := handler(ctx); err != nil {
+			ctx.Error(err)
+		}
+	}, handler)
+}
@@ -0,0 +1,124 @@
+package rou
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestErrorHandlers(t *testing.T) {
+	errUserNotFound := errors.New("user not found")
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		want   string
+	}{
+		{
+			name:   "HTTP error",
+			err:    NewHTTPError(http.StatusNotFound, "User not found").Wrap(errUserNotFound),
+			status: http.StatusNotFound,
+			want:   `{"error":{"message":"User not found","code":404},"body":null}`,
+		},
+		{
+			name:   "HTTP error with code and details",
+			err:    NewHTTPError(http.StatusConflict, "User exists").WithCode(1001).WithDetails([]string{"email"}),
+			status: http.StatusConflict,
+			want:   `{"error":{"message":"User exists","code":1001,"details":["email"]},"body":null}`,
+		},
+		{
+			name:   "wrapped HTTP error",
+			err:    fmt.Errorf("find user: %w", NewHTTPError(http.StatusBadRequest, "Bad id")),
+			status: http.StatusBadRequest,
+			want:   `{"error":{"message":"Bad id","code":400},"body":null}`,
+		},
+		{
+			name:   "unknown error",
+			err:    errUserNotFound,
+			status: http.StatusInternalServerError,
+			want:   `{"error":{"message":"Internal server error","code":500},"body":null}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := NewRouter()
+			router.Get("/users", WithError(func(ctx *Context) error {
+				return test.err
+			}))
+
+			newServer := httptest.NewServer(router)
+			defer newServer.Close()
+			res, _ := http.Get(newServer.URL + "/users")
+			bytesRes, _ := io.ReadAll(res.Body)
+
+			if res.StatusCode != test.status {
+				t.Errorf("expected status %d, got %d", test.status, res.StatusCode)
+			}
+			if string(bytesRes) != test.want {
+				t.Errorf("Response is not the same. Got - %s, want %s", bytesRes, test.want)
+			}
+		})
+	}
+
+	t.Run("no error", func(t *testing.T) {
+		router := NewRouter()
+		router.Get("/users", WithError(func(ctx *Context) error {
+			ctx.SuccessJSONResponse("ok")
+			return nil
+		}))
+
+		newServer := httptest.NewServer(router)
+		defer newServer.Close()
+		res, _ := http.Get(newServer.URL + "/users")
+		bytesRes, _ := io.ReadAll(res.Body)
+
+		want := `{"error":null,"body":"ok"}`
+		if string(bytesRes) != want {
+			t.Errorf("Response is not the same. Got - %s, want %s", bytesRes, want)
+		}
+	})
+
+	t.Run("custom error handler renders errors of handlers and router", func(t *testing.T) {
+		var handled []error
+		router := NewRouter()
+		router.ErrorHandler = func(ctx *Context, err error) {
+			handled = append(handled, err)
+			httpErr := AsHTTPError(err)
+			ctx.ResponseWriter().WriteHeader(httpErr.Status)
+			io.WriteString(ctx.ResponseWriter(), httpErr.Message)
+		}
+		router.Post("/users", WithError(func(ctx *Context) error {
+			return errUserNotFound
+		}))
+
+		newServer := httptest.NewServer(router)
+		defer newServer.Close()
+
+		res, _ := http.Post(newServer.URL+"/users", "application/json", nil)
+		bytesRes, _ := io.ReadAll(res.Body)
+		if string(bytesRes) != MessageInternalServerError {
+			t.Errorf("expected %q, got %q", MessageInternalServerError, string(bytesRes))
+		}
+
+		res, _ = http.Get(newServer.URL + "/users")
+		bytesRes, _ = io.ReadAll(res.Body)
+		if res.StatusCode != http.StatusMethodNotAllowed || string(bytesRes) != MessageMethodNotAllowed {
+			t.Errorf("expected %q, got %d %q", MessageMethodNotAllowed, res.StatusCode, string(bytesRes))
+		}
+
+		res, _ = http.Get(newServer.URL + "/posts")
+		bytesRes, _ = io.ReadAll(res.Body)
+		if res.StatusCode != http.StatusNotFound || string(bytesRes) != MessagePageNotFound {
+			t.Errorf("expected %q, got %d %q", MessagePageNotFound, res.StatusCode, string(bytesRes))
+		}
+
+		if len(handled) != 3 || !errors.Is(handled[0], errUserNotFound) {
+			t.Errorf("wrong handled errors: %v", handled)
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package rou
+
+import "strings"
+
+// Methods to register routes, implemented by SimpleRouter and Group
+type Registrar interface {
+	Handle(method string, route string, handler func(*Context)) RouterMethods
+	Get(route string, handler func(*Context)) RouterMethods
+	Post(route string, handler func(*Context)) RouterMethods
+	Put(route string, handler func(*Context)) RouterMethods
+	Patch(route string, handler func(*Context)) RouterMethods
+	Delete(route string, handler func(*Context)) RouterMethods
+	Head(route string, handler func(*Context)) RouterMethods
+	Options(route string, handler func(*Context)) RouterMethods
+	Group(prefix string, configure ...func(*Group)) *Group
+}
+
+var (
+	_ Registrar = &SimpleRouter{}
+	_ Registrar = &Group{}
+)
+
+// Set of routes with shared path prefix and middlewares
+//
+// Middlewares of the group are triggered after middlewares of the parent group and before middlewares of the route.
+type Group struct {
+	prefix      string
+	parent      *Group
+	routes      *routes
+	middlewares []Middleware
+}
+
+// Joins prefix of the group and route path
+func joinPaths(prefix string, route string) string {
+	if route == "" {
+		return prefix
+	}
+	return strings.TrimRight(prefix, "/") + "/" + strings.TrimLeft(route, "/")
+}
+
+// Creates group of routes with the prefix.
+//
+// Routes can be added to the returned group or inside configure functions.
+//
+//	router.Group("/api/v1", func(api *rou.Group) {
+//		api.Use(AuthMiddleware)
+//		api.Get("/users/:id", GET_UserHandler)
+//	})
+func (sr SimpleRouter) Group(prefix string, configure ...func(*Group)) *Group {
+	group := &Group{prefix: joinPaths("/", prefix), routes: sr.Routes}
+	for _, fn := range configure {
+		fn(group)
+	}
+	return group
+}
+
+// Creates nested group, its prefix is joined with the prefix of the parent group
+// and middlewares of the parent group are triggered before its own ones.
+func (g *Group) Group(prefix string, configure ...func(*Group)) *Group {
+	group := &Group{prefix: joinPaths(g.prefix, prefix), parent: g, routes: g.routes}
+	for _, fn := range configure {
+		fn(group)
+	}
+	return group
+}
+
+// Returns full path prefix of the group
+func (g *Group) Prefix() string {
+	return g.prefix
+}
+
+// Store middlewares for all routes of the group and nested groups
+func (g *Group) Use(middlewares ...MiddlewareFunction) {
+	g.middlewares = append(g.middlewares, adaptAll(middlewares)...)
+	g.routes.invalidate()
+}
+
+// Store middlewares which wrap handlers of all routes of the group and nested groups, the first one is the outermost
+func (g *Group) Wrap(middlewares ...Middleware) {
+	g.middlewares = append(g.middlewares, middlewares...)
+	g.routes.invalidate()
+}
+
+// Add route by any method
+func (g *Group) Handle(method string, route string, handler func(*Context)) RouterMethods {
+	newRoute := g.routes.storeRoute(method, joinPaths(g.prefix, route), handler)
+	newRoute.group = g
+	return newRoute
+}
+
+// Add route by method GET
+func (g *Group) Get(route string, handler func(*Context)) RouterMethods {
+	return g.Handle(MethodGet, route, handler)
+}
+
+// Add route by method POST
+func (g *Group) Post(route string, handler func(*Context)) RouterMethods {
+	return g.Handle(MethodPost, route, handler)
+}
+
+// Add route by method PUT
+func (g *Group) Put(route string, handler func(*Context)) RouterMethods {
+	return g.Handle(MethodPut, route, handler)
+}
+
+// Add route by method PATCH
+func (g *Group) Patch(route string, handler func(*Context)) RouterMethods {
+	return g.Handle(MethodPatch, route, handler)
+}
+
+// Add route by method DELETE
+func (g *Group) Delete(route string, handler func(*Context)) RouterMethods {
+	return g.Handle(MethodDelete, route, handler)
+}
+
+// Add route by method OPTIONS
+func (g *Group) Options(route string, handler func(*Context)) RouterMethods {
+	return g.Handle(MethodOptions, route, handler)
+}
+
+// Add route by method HEAD
+func (g *Group) Head(route string, handler func(*Context)) RouterMethods {
+	return g.Handle(MethodHead, route, handler)
+}
+
+// Returns middlewares of the group and all its parents starting from the root one
+func (g *Group) allMiddlewares() []Middleware {
+	var middlewares []Middleware
+	if g.parent != nil {
+		middlewares = g.parent.allMiddlewares()
+	}
+	return append(middlewares, g.middlewares...)
+}
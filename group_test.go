@@ -0,0 +1,116 @@
+package rou
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGroups(t *testing.T) {
+	t.Run("routes with group prefix", func(t *testing.T) {
+		router := NewRouter()
+		router.Group("/api/v1", func(api *Group) {
+			api.Get("/users/:id", func(ctx *Context) {
+				io.WriteString(ctx.ResponseWriter(), "user "+ctx.RouterParams().Get("id"))
+			})
+			api.Get("/", func(ctx *Context) {
+				io.WriteString(ctx.ResponseWriter(), "index")
+			})
+		})
+
+		newServer := httptest.NewServer(router)
+		defer newServer.Close()
+
+		res, _ := http.Get(newServer.URL + "/api/v1/users/10")
+		bytesRes, _ := io.ReadAll(res.Body)
+		if string(bytesRes) != "user 10" {
+			t.Errorf("expected %q, got %q", "user 10", string(bytesRes))
+		}
+
+		res, _ = http.Get(newServer.URL + "/api/v1")
+		bytesRes, _ = io.ReadAll(res.Body)
+		if string(bytesRes) != "index" {
+			t.Errorf("expected %q, got %q", "index", string(bytesRes))
+		}
+
+		res, _ = http.Get(newServer.URL + "/users/10")
+		if res.StatusCode != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, res.StatusCode)
+		}
+	})
+
+	t.Run("nested groups", func(t *testing.T) {
+		router := NewRouter()
+		api := router.Group("api")
+		admin := api.Group("/admin/")
+		admin.Post("users", func(ctx *Context) {})
+
+		if admin.Prefix() != "/api/admin/" {
+			t.Errorf("wrong prefix. Got - %q, want - %q", admin.Prefix(), "/api/admin/")
+		}
+		routes := router.GetRoutes(MethodPost)
+		if len(routes) != 1 || routes[0].Path != "/api/admin/users" {
+			t.Errorf("wrong routes: %v", routes)
+		}
+	})
+
+	t.Run("middlewares are triggered in order", func(t *testing.T) {
+		router := NewRouter()
+
+		var calls []string
+		track := func(name string) MiddlewareFunction {
+			return func(w http.ResponseWriter, r *http.Request) bool {
+				calls = append(calls, name)
+				return true
+			}
+		}
+
+		router.Use(track("router"))
+		api := router.Group("/api")
+		api.Use(track("api"))
+		admin := api.Group("/admin")
+		admin.Get("/users", func(ctx *Context) {
+			calls = append(calls, "handler")
+		}).Middleware(track("route"))
+		admin.Use(track("admin"))
+
+		newServer := httptest.NewServer(router)
+		defer newServer.Close()
+		http.Get(newServer.URL + "/api/admin/users")
+
+		want := "router api admin route handler"
+		if strings.Join(calls, " ") != want {
+			t.Errorf("wrong order of middlewares. Got - %q, want - %q", strings.Join(calls, " "), want)
+		}
+	})
+
+	t.Run("group middleware stops request", func(t *testing.T) {
+		router := NewRouter()
+		router.Group("/api", func(api *Group) {
+			api.Use(middlewares...)
+			api.Get("/users", func(ctx *Context) {
+				io.WriteString(ctx.ResponseWriter(), "Should not run this function")
+			})
+		})
+		router.Get("/public", func(ctx *Context) {
+			io.WriteString(ctx.ResponseWriter(), "public")
+		})
+
+		newServer := httptest.NewServer(router)
+		defer newServer.Close()
+
+		res, _ := http.Get(newServer.URL + "/api/users")
+		bytesRes, _ := io.ReadAll(res.Body)
+		if string(bytesRes) != authError {
+			t.Errorf("expected %q, got %q", authError, string(bytesRes))
+		}
+
+		res, _ = http.Get(newServer.URL + "/public")
+		bytesRes, _ = io.ReadAll(res.Body)
+		if string(bytesRes) != "public" {
+			t.Errorf("expected %q, got %q", "public", string(bytesRes))
+		}
+	})
+}
@@ -2,7 +2,10 @@ package rou
 
 import (
 	"net/http"
+	"reflect"
+	"sort"
 	"strings"
+	"sync/atomic"
 )
 
 const (
@@ -16,14 +19,27 @@ const (
 )
 
 const (
-	MessageBodyIsNotValid   = "Request body is not valid"
-	MessageMethodNotAllowed = "Method not allowed"
-	MessagePageNotFound     = "Page not found"
+	MessageBodyIsNotValid       = "Request body is not valid"
+	MessageRequestIsNotValid    = "Request is not valid"
+	MessageUnsupportedMediaType = "Unsupported media type"
+	MessageBodyIsTooLarge       = "Request body is too large"
+	MessageNotAcceptable        = "Not acceptable"
+	MessageMethodNotAllowed     = "Method not allowed"
+	MessagePageNotFound         = "Page not found"
+	MessageInternalServerError  = "Internal server error"
 )
 
 type MiddlewareFunction func(http.ResponseWriter, *http.Request) bool
+
+// Configures registered route.
+//
+// If the route can not be registered, e.g. it duplicates another route, the returned value is not used to serve requests
+// and every call of its methods is reported by SimpleRouter.Validate and MustCompile with the call site and the registration site.
 type RouterMethods interface {
 	Middleware(middlewares ...MiddlewareFunction)
+	Wrap(middlewares ...Middleware)
+	Name(name string) RouterMethods
+	Describe(doc RouteDoc) RouterMethods
 }
 
 type routerBuilder struct {
@@ -50,7 +66,25 @@ func (r routerBuilder) Get(name string) string {
 type Route struct {
 	Path        string
 	Handler     func(*Context)
-	middlewares []MiddlewareFunction
+	middlewares []Middleware
+	paramNames  []string
+	// Indexes of path segments with params, they are used to find raw values of params
+	paramSegments []int
+	method        string
+	location      string
+	group         *Group
+	mount         *mount
+	name          string
+	doc           *RouteDoc
+	// Types of request and response of handler registered by HandleJSON
+	requestType  reflect.Type
+	responseType reflect.Type
+	// Routes in which the route is stored, it is nil if the route can not be stored
+	routes *routes
+	// Reason why the route is not stored and routes which collect errors of calls on it
+	rejection *routeRejection
+	// Handler wrapped by middlewares, it stores composedHandler
+	composed atomic.Value
 }
 
 // Store all middllewares for a specific router
@@ -58,65 +92,241 @@ type Route struct {
 // Every middleware should return TRUE if the rule succeeds
 // If the middleware returns FALSE - other middlewares will not be triggered
 func (r *Route) Middleware(middlewares ...MiddlewareFunction) {
+	r.reportRejected("Middleware")
+	r.middlewares = append(r.middlewares, adaptAll(middlewares)...)
+	r.composed.Store(composedHandler{})
+}
+
+// Store middlewares which wrap the handler of the route, the first one is the outermost
+func (r *Route) Wrap(middlewares ...Middleware) {
+	r.reportRejected("Wrap")
 	r.middlewares = append(r.middlewares, middlewares...)
+	r.composed.Store(composedHandler{})
 }
 
-type existingRoute struct {
-	Method string
-	Path   string
+// Returns version of middlewares of groups and router which the route depends on
+func (r *Route) middlewaresVersion() uint64 {
+	if r.group != nil {
+		return r.group.routes.middlewaresVersion()
+	}
+	if r.routes != nil {
+		return r.routes.middlewaresVersion()
+	}
+	return 0
+}
+
+// Runs handler of the route wrapped by middlewares of its groups and its own middlewares.
+//
+// The wrapped handler is built once and rebuilt only after middlewares of the route, its groups or router are changed.
+func (r *Route) serve(ctx *Context) {
+	version := r.middlewaresVersion()
+	if cached, ok := r.composed.Load().(composedHandler); ok && cached.handler != nil && cached.version == version {
+		cached.handler(ctx)
+		return
+	}
+
+	var middlewares []Middleware
+	if r.group != nil {
+		middlewares = r.group.allMiddlewares()
+	}
+	handler := chain(r.Handler, append(middlewares, r.middlewares...))
+	r.composed.Store(composedHandler{version: version, handler: handler})
+	handler(ctx)
 }
 
 type routes struct {
-	existingRoutesWithMethod map[existingRoute]bool
-	routes                   map[string][]*Route
+	trees       map[string]*tree
+	routes      map[string][]*Route
+	errors      []*RouteConflictError
+	constraints map[string]Constraint
+	mounts      []*mount
+	names       map[string]*Route
+	// All stored routes in order of registration
+	list []*Route
+	// Incremented when middlewares of the router or its groups are changed, so wrapped handlers are rebuilt
+	version uint64
+	// Handler of the router wrapped by its middlewares, it stores composedHandler
+	composed atomic.Value
+}
+
+// Handler wrapped by middlewares and version of middlewares it is built with
+type composedHandler struct {
+	version uint64
+	handler Handler
+}
+
+func (r *routes) middlewaresVersion() uint64 {
+	return atomic.LoadUint64(&r.version)
+}
+
+// Marks handlers wrapped by middlewares of the router and its groups as outdated
+func (r *routes) invalidate() {
+	atomic.AddUint64(&r.version, 1)
 }
 
 // Stores route to if it is not exists
+//
+// The route is always returned, if it can not be stored the conflict is saved to be reported by SimpleRouter.Validate
+// and the returned route is not used to serve requests, calls of its methods are reported too.
 func (r *routes) storeRoute(method string, route string, handler func(*Context)) *Route {
-	newRoute := existingRoute{Method: method, Path: route}
-	if !r.existingRoutesWithMethod[newRoute] {
-		r.existingRoutesWithMethod[newRoute] = true
-		newRoute := &Route{Path: route, Handler: handler}
-		r.routes[method] = append(r.routes[method], newRoute)
+	methodTree, ok := r.trees[method]
+	if !ok {
+		methodTree = newTree(method, r.constraints)
+		r.trees[method] = methodTree
+	}
+
+	newRoute := &Route{Path: route, Handler: handler, method: method, location: callerLocation()}
+	ambiguous := len(methodTree.ambiguous)
+	if err := methodTree.insert(newRoute); err != nil {
+		r.errors = append(r.errors, err)
+		newRoute.rejection = &routeRejection{routes: r, err: err}
 		return newRoute
 	}
+	r.errors = append(r.errors, methodTree.ambiguous[ambiguous:]...)
+	r.routes[method] = append(r.routes[method], newRoute)
+	r.list = append(r.list, newRoute)
+	newRoute.routes = r
+	return newRoute
+}
+
+// Finds route by method and request path, returns values of route params in order of their names in route
+//
+// Routes with method GET are used for HEAD requests if there is no route with method HEAD.
+// Routes of mounted handlers are used if there is no route for the method.
+func (r routes) lookup(method string, requestPath string) (*Route, []string) {
+	if route, values := r.lookupMethod(method, requestPath); route != nil {
+		return route, values
+	}
+	if method == MethodHead {
+		if route, values := r.lookupMethod(MethodGet, requestPath); route != nil {
+			return route, values
+		}
+	}
+	return r.lookupMethod(methodAny, requestPath)
+}
+
+func (r routes) lookupMethod(method string, requestPath string) (*Route, []string) {
+	if methodTree, ok := r.trees[method]; ok {
+		return methodTree.lookup(requestPath)
+	}
+	return nil, nil
+}
+
+// Returns route with any method for the given path
+func (r routes) anyRoute(requestPath string) *Route {
+	for method, methodTree := range r.trees {
+		if method == methodAny {
+			continue
+		}
+		if route, _ := methodTree.lookup(requestPath); route != nil {
+			return route
+		}
+	}
 	return nil
 }
 
-// Check for route exists in Routes with given method and path
+// Check for route exists in Routes with any method and given path
 func (r routes) Exists(requestPath string) bool {
-	for route := range r.existingRoutesWithMethod {
-		_, equal := isEqualPaths(route.Path, requestPath)
-		if equal {
-			return true
+	return len(r.Allowed(requestPath)) > 0
+}
+
+// Returns sorted list of methods which have routes for the given path
+//
+// HEAD is allowed if there is a route with method GET and OPTIONS is allowed if there is a route with any method.
+func (r routes) Allowed(requestPath string) []string {
+	allowed := make(map[string]bool)
+	for method, methodTree := range r.trees {
+		if method == methodAny {
+			continue
+		}
+		if route, _ := methodTree.lookup(requestPath); route != nil {
+			allowed[method] = true
 		}
 	}
-	return false
+	if len(allowed) == 0 {
+		return nil
+	}
+	if allowed[MethodGet] {
+		allowed[MethodHead] = true
+	}
+	allowed[MethodOptions] = true
+
+	methods := make([]string, 0, len(allowed))
+	for method := range allowed {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return methods
 }
 
+// Returns routes with the method including routes of mounted routers
 func (r routes) GetRoutes(method string) []*Route {
-	return r.routes[method]
+	mounted := r.mountedRoutes(method)
+	if len(mounted) == 0 {
+		return r.routes[method]
+	}
+	result := make([]*Route, 0, len(r.routes[method])+len(mounted))
+	result = append(result, r.routes[method]...)
+	return append(result, mounted...)
 }
 
 // Initial struct to create HTTP server provide this structure to http.ListenAndServe function
 // It has a list of routes  which is stored to serve
 type SimpleRouter struct {
-	Routes      *routes
+	Routes *routes
+	// Content type which is preferred by Context.Render if the client accepts several formats equally
 	ContentType string
-	middlewares []MiddlewareFunction
+	// Reports ambiguous routes in Validate and refuses to run server if there are any errors in routes
+	Strict bool
+	// Renders errors returned by handlers and errors of router like 404 and 405, DefaultErrorHandler is used if it is nil
+	ErrorHandler ErrorHandler
+	// Defines layout of JSON responses and errors rendered by DefaultErrorHandler, DefaultEnvelope is used if it is nil
+	Envelope Envelope
+	// Handles requests without matching route, by default renders 404 error by ErrorHandler
+	NotFound Handler
+	// Handles requests if there are routes for the path only with other methods,
+	// by default renders 405 error by ErrorHandler. Allow header is set before it is called.
+	MethodNotAllowed Handler
+	// Defines how request path which differs from the registered route by trailing slash or is not clean is handled
+	PathPolicy PathPolicy
+	// Matches routes by escaped path of request, so encoded slashes are kept inside of params,
+	// values of params are decoded once and their raw values are available by Context.RawRouterParams
+	UseRawPath bool
+	// Max size of request body which is read by Context.Bind, DefaultMaxBodySize is used if it is 0
+	// and size is not limited if it is negative
+	MaxBodySize int64
+	// Rejects JSON and form bodies with fields which are not defined in destination of Context.Bind
+	DisallowUnknownFields bool
+	// Validates values decoded by Context.Bind, BindQuery, BindParams and BindHeader
+	Validator   *Validator
+	middlewares []Middleware
+	// Renderers of Context.Render in order of preference
+	renderers []renderer
+	// Mappers of errors to Problem which are used by ProblemErrorHandler
+	problemMappers []ProblemMapper
 }
 
 // Create a new SimpleRouter instance
 func NewRouter() *SimpleRouter {
 	routes := routes{
-		existingRoutesWithMethod: make(map[existingRoute]bool),
-		routes:                   make(map[string][]*Route),
+		trees:       make(map[string]*tree),
+		routes:      make(map[string][]*Route),
+		constraints: defaultConstraints(),
+		names:       make(map[string]*Route),
 	}
-	return &SimpleRouter{Routes: &routes}
+	return &SimpleRouter{Routes: &routes, Validator: NewValidator(), renderers: defaultRenderers()}
 }
 
+// Store middlewares which are triggered for every request before route matching
 func (sr *SimpleRouter) Use(middlewares ...MiddlewareFunction) {
+	sr.middlewares = append(sr.middlewares, adaptAll(middlewares)...)
+	sr.Routes.invalidate()
+}
+
+// Store middlewares which wrap handling of every request including route matching, the first one is the outermost
+func (sr *SimpleRouter) Wrap(middlewares ...Middleware) {
 	sr.middlewares = append(sr.middlewares, middlewares...)
+	sr.Routes.invalidate()
 }
 
 func (sr SimpleRouter) GetRoutes(method string) []*Route {
@@ -127,6 +337,11 @@ func (sr *SimpleRouter) storeRoute(method string, route string, handler func(*Co
 	return sr.Routes.storeRoute(method, route, handler)
 }
 
+// Add route by any method
+func (sr SimpleRouter) Handle(method string, route string, handler func(*Context)) RouterMethods {
+	return sr.storeRoute(method, route, handler)
+}
+
 // Add route by method GET
 func (sr SimpleRouter) Get(route string, handler func(*Context)) RouterMethods {
 	return sr.storeRoute(MethodGet, route, handler)
@@ -153,106 +368,95 @@ func (sr SimpleRouter) Delete(route string, handler func(*Context)) RouterMethod
 }
 
 // Add route by method OPTIONS
-func (sr SimpleRouter) Options(route string, handler func(*Context)) {
-	sr.storeRoute(MethodOptions, route, handler)
+//
+// Requests with method OPTIONS are answered automatically with Allow header if there is no such route.
+func (sr SimpleRouter) Options(route string, handler func(*Context)) RouterMethods {
+	return sr.storeRoute(MethodOptions, route, handler)
 }
 
 // Add route by method HEAD
+//
+// Requests with method HEAD are handled by route with method GET without response body if there is no such route.
 func (sr SimpleRouter) Head(route string, handler func(*Context)) RouterMethods {
 	return sr.storeRoute(MethodHead, route, handler)
 }
 
-func (sr SimpleRouter) createContext(w http.ResponseWriter, r *http.Request) *Context {
+func (sr *SimpleRouter) createContext(w http.ResponseWriter, r *http.Request) *Context {
+	response := newResponseWriter(w)
 	return &Context{
-		responseWriter: w,
+		responseWriter: response,
+		response:       response,
 		request:        r,
 		routeParams:    &routerBuilder{value: make(map[string]string)},
+		rawRouteParams: &routerBuilder{value: make(map[string]string)},
+		router:         sr,
 	}
 }
 
-func prepareURLChunks(url string) []string {
-	return strings.Split(strings.Trim(url, "/"), "/")
+// Implements an http.Handler interface to use it like server handler in http.ListenAndServe
+func (sr *SimpleRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := sr.createContext(w, r)
+	sr.handler()(ctx)
 }
 
-func isEqualPaths(route string, requestPath string) (*map[string]string, bool) {
-	params := make(map[string]string)
-
-	if route == requestPath {
-		return &params, true
-	}
-
-	clearRoutePath := prepareURLChunks(route)
-	clearRequestPath := prepareURLChunks(requestPath)
-
-	if len(clearRoutePath) != len(clearRequestPath) {
-		return nil, false
+// Returns route matching wrapped by middlewares of the router, it is built once and rebuilt after middlewares are changed
+func (sr *SimpleRouter) handler() Handler {
+	version := sr.Routes.middlewaresVersion()
+	if cached, ok := sr.Routes.composed.Load().(composedHandler); ok && cached.handler != nil && cached.version == version {
+		return cached.handler
 	}
-
-	for i := 0; i < len(clearRoutePath); i++ {
-		routeChunk := clearRoutePath[i]
-		if clearRoutePath[i][0] == ':' {
-			params[routeChunk[1:]] = clearRequestPath[i]
-		} else {
-			if clearRequestPath[i] != routeChunk {
-				return nil, false
-			}
-		}
-	}
-	return &params, true
+	handler := chain(func(ctx *Context) { ctx.router.dispatch(ctx) }, sr.middlewares)
+	sr.Routes.composed.Store(composedHandler{version: version, handler: handler})
+	return handler
 }
 
-func runMiddleWares(route *Route, w http.ResponseWriter, r *http.Request) bool {
-	for _, middleware := range route.middlewares {
-		if !middleware(w, r) {
-			return false
-		}
+// Finds route for request of the context and serves it
+func (sr *SimpleRouter) dispatch(ctx *Context) {
+	r := ctx.Request()
+	requestPath := sr.matchPath(r)
+	route, values, location, rejected := sr.lookupByPolicy(r, requestPath)
+	if location != "" {
+		redirect(ctx, location)
+		return
 	}
-	return true
-}
-
-// Implements an http.Handler interface to use it like server handler in http.ListenAndServe
-func (sr *SimpleRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	for _, middleware := range sr.middlewares {
-		if !middleware(w, r) {
-			return
+	if route != nil {
+		sr.setParams(ctx, route, values)
+		if r.Method == MethodHead && route.method == MethodGet {
+			ctx.response.discard = true
 		}
+		route.serve(ctx)
+		return
 	}
 
-	ctx := sr.createContext(w, r)
-	routesByMethod := sr.GetRoutes(r.Method)
-
-ROUTES_BY_METHOD:
-	for _, route := range routesByMethod {
-		if r.URL.Path == route.Path {
-			if !runMiddleWares(route, w, r) {
-				return
-			}
-			route.Handler(ctx)
+	if allowed := sr.Routes.Allowed(requestPath); len(allowed) > 0 && !rejected {
+		ctx.ResponseWriter().Header().Set("Allow", strings.Join(allowed, ", "))
+		if r.Method == MethodOptions {
+			ctx.ResponseWriter().WriteHeader(http.StatusNoContent)
 			return
 		}
-
-		params, equal := isEqualPaths(route.Path, r.URL.Path)
-		if !equal {
-			continue ROUTES_BY_METHOD
-		}
-		if !runMiddleWares(route, w, r) {
+		if sr.MethodNotAllowed != nil {
+			sr.MethodNotAllowed(ctx)
 			return
 		}
-		for name, value := range *params {
-			ctx.RouterParams().Set(name, value)
-		}
-		route.Handler(ctx)
+		ctx.Error(NewHTTPError(http.StatusMethodNotAllowed, MessageMethodNotAllowed))
 		return
 	}
 
-	if sr.Routes.Exists(r.URL.Path) {
-		ctx.ErrorJSONResponse(http.StatusMethodNotAllowed, MessageMethodNotAllowed)
+	if sr.NotFound != nil {
+		sr.NotFound(ctx)
 		return
 	}
-	ctx.ErrorJSONResponse(http.StatusNotFound, MessagePageNotFound)
+	ctx.Error(NewHTTPError(http.StatusNotFound, MessagePageNotFound))
 }
 
 // Runs server with http.ListenAndServe
+//
+// In strict mode routes are validated before the server starts.
 func (sr *SimpleRouter) RunServer(addr string) error {
+	if sr.Strict {
+		if err := sr.Validate(); err != nil {
+			return err
+		}
+	}
 	return http.ListenAndServe(addr, sr)
 }
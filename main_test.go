@@ -219,3 +219,169 @@ func TestServeHTTP(t *testing.T) {
 		}
 	})
 }
+
+func TestNotFoundHandlers(t *testing.T) {
+	fakeHandler := func(ctx *Context) {}
+
+	t.Run("Allow header of 405 response", func(t *testing.T) {
+		router := NewRouter()
+		router.Post("/users/:id", fakeHandler)
+		router.Delete("/users/:id", fakeHandler)
+		router.Put("/users/me", fakeHandler)
+
+		newServer := httptest.NewServer(router)
+		defer newServer.Close()
+		res, _ := http.Get(newServer.URL + "/users/10")
+
+		if res.StatusCode != http.StatusMethodNotAllowed {
+			t.Errorf("expected status %d, got %d", http.StatusMethodNotAllowed, res.StatusCode)
+		}
+		want := "DELETE, OPTIONS, POST"
+		if res.Header.Get("Allow") != want {
+			t.Errorf("wrong Allow header. Got - %q, want - %q", res.Header.Get("Allow"), want)
+		}
+	})
+
+	t.Run("custom handlers", func(t *testing.T) {
+		router := NewRouter()
+		router.Post("/users", fakeHandler)
+		router.NotFound = func(ctx *Context) {
+			ctx.ResponseWriter().WriteHeader(http.StatusNotFound)
+			io.WriteString(ctx.ResponseWriter(), "custom not found")
+		}
+		router.MethodNotAllowed = func(ctx *Context) {
+			ctx.ResponseWriter().WriteHeader(http.StatusMethodNotAllowed)
+			io.WriteString(ctx.ResponseWriter(), "allowed: "+ctx.ResponseWriter().Header().Get("Allow"))
+		}
+
+		newServer := httptest.NewServer(router)
+		defer newServer.Close()
+
+		res, _ := http.Get(newServer.URL + "/posts")
+		resBytes, _ := io.ReadAll(res.Body)
+		if res.StatusCode != http.StatusNotFound || string(resBytes) != "custom not found" {
+			t.Errorf("wrong response: %d %q", res.StatusCode, resBytes)
+		}
+
+		res, _ = http.Get(newServer.URL + "/users")
+		resBytes, _ = io.ReadAll(res.Body)
+		if res.StatusCode != http.StatusMethodNotAllowed || string(resBytes) != "allowed: OPTIONS, POST" {
+			t.Errorf("wrong response: %d %q", res.StatusCode, resBytes)
+		}
+	})
+
+	t.Run("handlers go through router middlewares", func(t *testing.T) {
+		router := NewRouter()
+		router.Use(middlewares...)
+		router.NotFound = func(ctx *Context) {
+			io.WriteString(ctx.ResponseWriter(), "Should not run this function")
+		}
+
+		newServer := httptest.NewServer(router)
+		defer newServer.Close()
+		res, _ := http.Get(newServer.URL + "/posts")
+		resBytes, _ := io.ReadAll(res.Body)
+
+		if string(resBytes) != authError {
+			t.Errorf("expected %q, got %q", authError, resBytes)
+		}
+	})
+}
+
+func TestAllowedMethods(t *testing.T) {
+	router := NewRouter()
+	fakeHandler := func(ctx *Context) {}
+	router.Get("/users", fakeHandler)
+	router.Post("/users", fakeHandler)
+	router.Patch("/users/:id", fakeHandler)
+
+	got := router.Routes.Allowed("/users")
+	want := []string{MethodGet, MethodHead, MethodOptions, MethodPost}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got - %v, want - %v", got, want)
+	}
+	if router.Routes.Allowed("/posts") != nil {
+		t.Errorf("expected no methods for unknown path")
+	}
+}
+
+func TestAutomaticHeadAndOptions(t *testing.T) {
+	getHandler := func(ctx *Context) {
+		ctx.ResponseWriter().Header().Set("X-Handler", "GET")
+		io.WriteString(ctx.ResponseWriter(), "Response body")
+	}
+
+	t.Run("HEAD request is handled by GET route without body", func(t *testing.T) {
+		router := NewRouter()
+		router.Get("/users/:id", getHandler)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/users/10", nil))
+
+		if w.Code != http.StatusOK || w.Header().Get("X-Handler") != "GET" {
+			t.Errorf("expected response of GET handler, got %d %v", w.Code, w.Header())
+		}
+		if w.Body.Len() != 0 {
+			t.Errorf("expected empty body, got %q", w.Body.String())
+		}
+	})
+
+	t.Run("HEAD route overrides GET route", func(t *testing.T) {
+		router := NewRouter()
+		router.Get("/users/:id", getHandler)
+		router.Head("/users/:id", func(ctx *Context) {
+			ctx.ResponseWriter().Header().Set("X-Handler", "HEAD")
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/users/10", nil))
+
+		if w.Header().Get("X-Handler") != "HEAD" {
+			t.Errorf("expected response of HEAD handler, got %v", w.Header())
+		}
+	})
+
+	t.Run("OPTIONS request is answered with Allow header", func(t *testing.T) {
+		router := NewRouter()
+		router.Get("/users/:id", getHandler)
+		router.Delete("/users/:id", getHandler)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodOptions, "/users/10", nil))
+
+		if w.Code != http.StatusNoContent {
+			t.Errorf("expected status %d, got %d", http.StatusNoContent, w.Code)
+		}
+		want := "DELETE, GET, HEAD, OPTIONS"
+		if w.Header().Get("Allow") != want {
+			t.Errorf("wrong Allow header. Got - %q, want - %q", w.Header().Get("Allow"), want)
+		}
+	})
+
+	t.Run("OPTIONS route overrides automatic answer", func(t *testing.T) {
+		router := NewRouter()
+		router.Get("/users/:id", getHandler)
+		router.Options("/users/:id", func(ctx *Context) {
+			ctx.ResponseWriter().WriteHeader(http.StatusOK)
+		}).Middleware(middlewares...)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodOptions, "/users/10", nil))
+
+		if w.Body.String() != authError {
+			t.Errorf("expected %q, got %q", authError, w.Body.String())
+		}
+	})
+
+	t.Run("OPTIONS request for unknown path", func(t *testing.T) {
+		router := NewRouter()
+		router.Get("/users/:id", getHandler)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodOptions, "/posts", nil))
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+}
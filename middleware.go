@@ -0,0 +1,43 @@
+package rou
+
+// Function which handles request
+type Handler func(*Context)
+
+// Middleware which wraps the next handler, it can run code before and after the handler,
+// replace request or response writer of the context or do not call the next handler at all.
+//
+//	func Timing(next rou.Handler) rou.Handler {
+//		return func(ctx *rou.Context) {
+//			start := time.Now()
+//			next(ctx)
+//			log.Println(ctx.Request().URL.Path, time.Since(start))
+//		}
+//	}
+type Middleware func(next Handler) Handler
+
+// Converts MiddlewareFunction to Middleware, the next handler is called only if middleware returns TRUE
+func Adapt(middleware MiddlewareFunction) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx *Context) {
+			if middleware(ctx.ResponseWriter(), ctx.Request()) {
+				next(ctx)
+			}
+		}
+	}
+}
+
+func adaptAll(middlewares []MiddlewareFunction) []Middleware {
+	result := make([]Middleware, 0, len(middlewares))
+	for _, middleware := range middlewares {
+		result = append(result, Adapt(middleware))
+	}
+	return result
+}
+
+// Wraps handler by middlewares, the first middleware is the outermost one
+func chain(handler Handler, middlewares []Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
@@ -2,9 +2,11 @@ package rou
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 )
 
@@ -123,3 +125,146 @@ func TestRouteMiddleware(t *testing.T) {
 	})
 
 }
+
+type contextKey string
+
+func TestWrapMiddleware(t *testing.T) {
+	t.Run("runs code after handler", func(t *testing.T) {
+		router := NewRouter()
+
+		var calls []string
+		track := func(name string) Middleware {
+			return func(next Handler) Handler {
+				return func(ctx *Context) {
+					calls = append(calls, "before "+name)
+					next(ctx)
+					calls = append(calls, "after "+name)
+				}
+			}
+		}
+
+		router.Wrap(track("router"))
+		router.Group("/api", func(api *Group) {
+			api.Wrap(track("group"))
+			api.Get("/users", func(ctx *Context) {
+				calls = append(calls, "handler")
+			}).Wrap(track("route"))
+		})
+
+		newServer := httptest.NewServer(router)
+		defer newServer.Close()
+		http.Get(newServer.URL + "/api/users")
+
+		want := []string{"before router", "before group", "before route", "handler", "after route", "after group", "after router"}
+		if !reflect.DeepEqual(calls, want) {
+			t.Errorf("wrong order of calls. Got - %v, want - %v", calls, want)
+		}
+	})
+
+	t.Run("replaces request passed downstream", func(t *testing.T) {
+		router := NewRouter()
+		router.Wrap(func(next Handler) Handler {
+			return func(ctx *Context) {
+				r := ctx.Request()
+				ctx.SetRequest(r.WithContext(context.WithValue(r.Context(), contextKey("user"), "melony")))
+				next(ctx)
+			}
+		})
+		router.Get("/users", func(ctx *Context) {
+			io.WriteString(ctx.ResponseWriter(), ctx.Request().Context().Value(contextKey("user")).(string))
+		})
+
+		newServer := httptest.NewServer(router)
+		defer newServer.Close()
+		res, _ := http.Get(newServer.URL + "/users")
+		bytesRes, _ := io.ReadAll(res.Body)
+
+		if string(bytesRes) != "melony" {
+			t.Errorf("expected %q, got %q", "melony", string(bytesRes))
+		}
+	})
+
+	t.Run("recovers panic of handler", func(t *testing.T) {
+		router := NewRouter()
+		router.Wrap(func(next Handler) Handler {
+			return func(ctx *Context) {
+				defer func() {
+					if recover() != nil {
+						ctx.ErrorJSONResponse(http.StatusInternalServerError, "Internal server error")
+					}
+				}()
+				next(ctx)
+			}
+		})
+		router.Get("/users", func(ctx *Context) {
+			panic("unexpected")
+		})
+
+		newServer := httptest.NewServer(router)
+		defer newServer.Close()
+		res, _ := http.Get(newServer.URL + "/users")
+
+		if res.StatusCode != http.StatusInternalServerError {
+			t.Errorf("expected status %d, got %d", http.StatusInternalServerError, res.StatusCode)
+		}
+	})
+
+	t.Run("boolean middlewares are adapted", func(t *testing.T) {
+		router := NewRouter()
+		router.Wrap(Adapt(middlewares[0]))
+		router.Get("/users", func(ctx *Context) {
+			io.WriteString(ctx.ResponseWriter(), "Should not run this function")
+		})
+
+		newServer := httptest.NewServer(router)
+		defer newServer.Close()
+		res, _ := http.Get(newServer.URL + "/users")
+		bytesRes, _ := io.ReadAll(res.Body)
+
+		if string(bytesRes) != authError {
+			t.Errorf("expected %q, got %q", authError, string(bytesRes))
+		}
+	})
+}
+
+func TestMiddlewaresAddedAfterRequests(t *testing.T) {
+	var calls []string
+	tag := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx *Context) {
+				calls = append(calls, name)
+				next(ctx)
+			}
+		}
+	}
+
+	router := NewRouter()
+	api := router.Group("/api")
+	route := api.Get("/users", func(ctx *Context) {
+		calls = append(calls, "handler")
+	})
+
+	serve := func() []string {
+		calls = nil
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/users", nil))
+		return calls
+	}
+
+	steps := []struct {
+		add  func()
+		want []string
+	}{
+		{add: func() {}, want: []string{"handler"}},
+		{add: func() { router.Wrap(tag("router")) }, want: []string{"router", "handler"}},
+		{add: func() { api.Wrap(tag("group")) }, want: []string{"router", "group", "handler"}},
+		{add: func() { route.Wrap(tag("route")) }, want: []string{"router", "group", "route", "handler"}},
+		{add: func() {}, want: []string{"router", "group", "route", "handler"}},
+	}
+
+	for i, step := range steps {
+		step.add()
+		if got := serve(); !reflect.DeepEqual(got, step.want) {
+			t.Errorf("step %d: got - %v, want - %v", i, got, step.want)
+		}
+	}
+}
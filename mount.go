@@ -0,0 +1,112 @@
+package rou
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Routes of mounted handlers are stored with this method and matched for requests with any method
+// if there is no route for the method of request
+const methodAny = ""
+
+// Handler mounted under path prefix
+type mount struct {
+	prefix   string
+	handler  http.Handler
+	segments int
+}
+
+// Returns number of segments in the path
+func countSegments(path string) int {
+	path = routeKey(path)
+	if path == "" {
+		return 0
+	}
+	return strings.Count(path, "/") + 1
+}
+
+// Returns the path without first n segments, the result always starts with "/"
+func dropSegments(path string, n int) string {
+	path = strings.TrimLeft(path, "/")
+	for i := 0; i < n; i++ {
+		idx := strings.IndexByte(path, '/')
+		if idx < 0 {
+			return "/"
+		}
+		path = path[idx+1:]
+	}
+	return "/" + path
+}
+
+// Returns copy of request with stripped prefix in URL.Path and URL.RawPath
+func (m *mount) stripPrefix(r *http.Request) *http.Request {
+	r2 := new(http.Request)
+	*r2 = *r
+	r2.URL = new(url.URL)
+	*r2.URL = *r.URL
+
+	r2.URL.Path = dropSegments(r.URL.Path, m.segments)
+	if r.URL.RawPath != "" {
+		r2.URL.RawPath = dropSegments(r.URL.RawPath, m.segments)
+		if unescaped, err := url.PathUnescape(r2.URL.RawPath); err != nil || unescaped != r2.URL.Path {
+			r2.URL.RawPath = ""
+		}
+	}
+	return r2
+}
+
+func (m *mount) serve(ctx *Context) {
+	m.handler.ServeHTTP(ctx.ResponseWriter(), m.stripPrefix(ctx.Request()))
+}
+
+func (r *routes) storeMount(prefix string, handler http.Handler) []*Route {
+	m := &mount{prefix: prefix, handler: handler, segments: countSegments(prefix)}
+	r.mounts = append(r.mounts, m)
+
+	mountRoutes := []*Route{
+		r.storeRoute(methodAny, prefix, m.serve),
+		r.storeRoute(methodAny, joinPaths(prefix, "*path"), m.serve),
+	}
+	for _, route := range mountRoutes {
+		route.mount = m
+	}
+	return mountRoutes
+}
+
+// Serves all requests which path starts with prefix by handler.
+//
+// The prefix is removed from URL.Path and URL.RawPath of request passed to the handler.
+// Routes registered with methods have priority over mounted handlers.
+// If handler is SimpleRouter its routes are returned by GetRoutes with the prefix.
+//
+//	router.Mount("/debug", http.DefaultServeMux)
+func (sr SimpleRouter) Mount(prefix string, handler http.Handler) {
+	sr.Routes.storeMount(prefix, handler)
+}
+
+// Serves all requests which path starts with prefix of the group and the prefix by handler.
+//
+// Middlewares of the group are triggered before the handler.
+func (g *Group) Mount(prefix string, handler http.Handler) {
+	for _, route := range g.routes.storeMount(joinPaths(g.prefix, prefix), handler) {
+		route.group = g
+	}
+}
+
+// Returns routes of mounted routers with the method
+func (r routes) mountedRoutes(method string) []*Route {
+	var result []*Route
+	for _, m := range r.mounts {
+		subRouter, ok := m.handler.(*SimpleRouter)
+		if !ok {
+			continue
+		}
+		for _, route := range subRouter.GetRoutes(method) {
+			mountedRoute := *route
+			mountedRoute.Path = joinPaths(m.prefix, route.Path)
+			result = append(result, &mountedRoute)
+		}
+	}
+	return result
+}
@@ -0,0 +1,102 @@
+package rou
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMount(t *testing.T) {
+	echoPath := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.Method+" "+r.URL.Path+" "+r.URL.RawPath)
+	})
+
+	tests := []struct {
+		name        string
+		method      string
+		requestPath string
+		want        string
+	}{
+		{name: "strips prefix", method: http.MethodGet, requestPath: "/legacy/users/10", want: "GET /users/10 "},
+		{name: "exact prefix", method: http.MethodGet, requestPath: "/legacy", want: "GET / "},
+		{name: "keeps trailing slash", method: http.MethodGet, requestPath: "/legacy/users/", want: "GET /users/ "},
+		{name: "any method", method: "PROPFIND", requestPath: "/legacy/files", want: "PROPFIND /files "},
+		{name: "preserves raw path", method: http.MethodGet, requestPath: "/legacy/files/a%2Fb", want: "GET /files/a/b /files/a%2Fb"},
+		{name: "route of router has priority", method: http.MethodGet, requestPath: "/legacy/own", want: "own"},
+	}
+
+	router := NewRouter()
+	router.Mount("/legacy", echoPath)
+	router.Get("/legacy/own", func(ctx *Context) {
+		io.WriteString(ctx.ResponseWriter(), "own")
+	})
+
+	newServer := httptest.NewServer(router)
+	defer newServer.Close()
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request, _ := http.NewRequest(test.method, newServer.URL+test.requestPath, nil)
+			res, err := http.DefaultClient.Do(request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			bytesRes, _ := io.ReadAll(res.Body)
+			if string(bytesRes) != test.want {
+				t.Errorf("expected %q, got %q", test.want, string(bytesRes))
+			}
+		})
+	}
+
+	t.Run("mounted router", func(t *testing.T) {
+		subRouter := NewRouter()
+		subRouter.Get("/users/:id", func(ctx *Context) {
+			io.WriteString(ctx.ResponseWriter(), "user "+ctx.RouterParams().Get("id"))
+		})
+
+		router := NewRouter()
+		router.Mount("/team", subRouter)
+
+		newServer := httptest.NewServer(router)
+		defer newServer.Close()
+
+		res, _ := http.Get(newServer.URL + "/team/users/10")
+		bytesRes, _ := io.ReadAll(res.Body)
+		if string(bytesRes) != "user 10" {
+			t.Errorf("expected %q, got %q", "user 10", string(bytesRes))
+		}
+
+		routes := router.GetRoutes(MethodGet)
+		if len(routes) != 1 || routes[0].Path != "/team/users/:id" {
+			t.Errorf("mounted routes are not returned: %v", routes)
+		}
+	})
+
+	t.Run("mount in group", func(t *testing.T) {
+		router := NewRouter()
+		router.Group("/api", func(api *Group) {
+			api.Use(middlewares...)
+			api.Mount("/legacy", echoPath)
+		})
+
+		newServer := httptest.NewServer(router)
+		defer newServer.Close()
+
+		res, _ := http.Get(newServer.URL + "/api/legacy/users")
+		bytesRes, _ := io.ReadAll(res.Body)
+		if string(bytesRes) != authError {
+			t.Errorf("expected %q, got %q", authError, string(bytesRes))
+		}
+
+		request, _ := http.NewRequest(http.MethodGet, newServer.URL+"/api/legacy/users", nil)
+		request.Header.Add("Authorization", "secret key")
+		request.Header.Add("X-Request-Data", "request data")
+		res, _ = http.DefaultClient.Do(request)
+		bytesRes, _ = io.ReadAll(res.Body)
+		if !strings.HasPrefix(string(bytesRes), "GET /users") {
+			t.Errorf("expected request to mounted handler, got %q", string(bytesRes))
+		}
+	})
+}
@@ -0,0 +1,375 @@
+package rou
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version of OpenAPI specification of generated documents
+const OpenAPIVersion = "3.1.0"
+
+// Description of the route which is used to generate OpenAPI document
+type RouteDoc struct {
+	// Unique id of the operation, name of the route is used if it is empty
+	OperationID string
+	Summary     string
+	Description string
+	Tags        []string
+	Deprecated  bool
+	// Value of the request body type, e.g. CreateUserRequest{}
+	Request any
+	// Value of the response body type, it is described inside of the response envelope
+	Response any
+	// Status of successful response, 200 is used if it is not set
+	Status int
+	// Value of struct type which fields with "query" tag describe query params
+	Query any
+	// Descriptions of path and query params by name
+	Params map[string]string
+	// Route is not included in OpenAPI document
+	Hidden bool
+}
+
+// Sets description of the route which is used by SimpleRouter.OpenAPI
+func (r *Route) Describe(doc RouteDoc) RouterMethods {
+	r.reportRejected("Describe")
+	r.doc = &doc
+	return r
+}
+
+type OpenAPIDocument struct {
+	OpenAPI    string                      `json:"openapi"`
+	Info       OpenAPIInfo                 `json:"info"`
+	Paths      map[string]*OpenAPIPathItem `json:"paths"`
+	Components *OpenAPIComponents          `json:"components,omitempty"`
+}
+
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Operations of the path and params which are common for all of them
+type OpenAPIPathItem struct {
+	Get        *OpenAPIOperation   `json:"get,omitempty"`
+	Put        *OpenAPIOperation   `json:"put,omitempty"`
+	Post       *OpenAPIOperation   `json:"post,omitempty"`
+	Delete     *OpenAPIOperation   `json:"delete,omitempty"`
+	Options    *OpenAPIOperation   `json:"options,omitempty"`
+	Head       *OpenAPIOperation   `json:"head,omitempty"`
+	Patch      *OpenAPIOperation   `json:"patch,omitempty"`
+	Parameters []*OpenAPIParameter `json:"parameters,omitempty"`
+}
+
+// Returns pointer to the operation field of the method or nil if the method is not supported by OpenAPI
+func (p *OpenAPIPathItem) operation(method string) **OpenAPIOperation {
+	switch method {
+	case MethodGet:
+		return &p.Get
+	case MethodPut:
+		return &p.Put
+	case MethodPost:
+		return &p.Post
+	case MethodDelete:
+		return &p.Delete
+	case MethodOptions:
+		return &p.Options
+	case MethodHead:
+		return &p.Head
+	case MethodPatch:
+		return &p.Patch
+	}
+	return nil
+}
+
+// Returns operations of the path by method
+func (p *OpenAPIPathItem) Operations() map[string]*OpenAPIOperation {
+	operations := make(map[string]*OpenAPIOperation)
+	for _, method := range []string{MethodGet, MethodPut, MethodPost, MethodDelete, MethodOptions, MethodHead, MethodPatch} {
+		if operation := *p.operation(method); operation != nil {
+			operations[method] = operation
+		}
+	}
+	return operations
+}
+
+type OpenAPIOperation struct {
+	OperationID string                      `json:"operationId,omitempty"`
+	Summary     string                      `json:"summary,omitempty"`
+	Description string                      `json:"description,omitempty"`
+	Tags        []string                    `json:"tags,omitempty"`
+	Deprecated  bool                        `json:"deprecated,omitempty"`
+	Parameters  []*OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*OpenAPIResponse `json:"responses"`
+}
+
+type OpenAPIParameter struct {
+	// Reference to the parameter in components, other fields are empty if it is set
+	Ref         string  `json:"$ref,omitempty"`
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema,omitempty"`
+}
+
+type OpenAPIRequestBody struct {
+	Description string                       `json:"description,omitempty"`
+	Required    bool                         `json:"required,omitempty"`
+	Content     map[string]*OpenAPIMediaType `json:"content"`
+}
+
+type OpenAPIMediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+type OpenAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*OpenAPIMediaType `json:"content,omitempty"`
+}
+
+type OpenAPIComponents struct {
+	Schemas    map[string]*Schema           `json:"schemas,omitempty"`
+	Parameters map[string]*OpenAPIParameter `json:"parameters,omitempty"`
+}
+
+// Converts path of the route to OpenAPI path template: "/users/:id<int>/*path" to "/users/{id}/{path}"
+func openAPIPath(route string) string {
+	segments := strings.Split(route, "/")
+	for i, segment := range segments {
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			name, _ := parseParam(segment)
+			segments[i] = "{" + name + "}"
+		case strings.HasPrefix(segment, "*"):
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// Returns schema of path param with the constraint
+func constraintSchema(constraints map[string]Constraint, expression string) *Schema {
+	switch expression {
+	case ConstraintInt:
+		return &Schema{Type: SchemaType{"integer"}}
+	case ConstraintUint:
+		return &Schema{Type: SchemaType{"integer"}, Minimum: float(0)}
+	case ConstraintUUID:
+		return &Schema{Type: SchemaType{"string"}, Format: "uuid"}
+	case ConstraintAlpha:
+		return &Schema{Type: SchemaType{"string"}, Pattern: "^[A-Za-z]+$"}
+	case ConstraintDate:
+		return &Schema{Type: SchemaType{"string"}, Format: "date"}
+	}
+
+	schema := &Schema{Type: SchemaType{"string"}}
+	if _, ok := constraints[expression]; ok || expression == "" {
+		return schema
+	}
+	if _, err := regexp.Compile(expression); err == nil {
+		schema.Pattern = "^(?:" + expression + ")$"
+	}
+	return schema
+}
+
+// Returns path params of the route in order of segments
+func pathParameters(route *Route, routePath string, descriptions map[string]string) []*OpenAPIParameter {
+	var constraints map[string]Constraint
+	if route.routes != nil {
+		constraints = route.routes.constraints
+	}
+
+	var parameters []*OpenAPIParameter
+	for _, segment := range strings.Split(routePath, "/") {
+		var name string
+		var schema *Schema
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			var expression string
+			name, expression = parseParam(segment)
+			schema = constraintSchema(constraints, expression)
+		case strings.HasPrefix(segment, "*"):
+			name, schema = segment[1:], &Schema{Type: SchemaType{"string"}}
+		default:
+			continue
+		}
+		parameters = append(parameters, &OpenAPIParameter{
+			Name:        name,
+			In:          "path",
+			Description: descriptions[name],
+			Required:    true,
+			Schema:      schema,
+		})
+	}
+	return parameters
+}
+
+// Returns params described by fields of struct with the tag, e.g. "query" or "header"
+func (g *schemaGenerator) taggedParameters(t reflect.Type, tag string, in string, descriptions map[string]string) []*OpenAPIParameter {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var parameters []*OpenAPIParameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+		description := field.Tag.Get("doc")
+		if description == "" {
+			description = descriptions[name]
+		}
+		parameters = append(parameters, &OpenAPIParameter{
+			Name:        name,
+			In:          in,
+			Description: description,
+			Schema:      g.schema(field.Type),
+		})
+	}
+	return parameters
+}
+
+// Returns TRUE if value of the type is decoded from body: it is not a struct
+// or it has fields which are not bound from route params, query params or headers
+func hasBodyFields(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return true
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.IsExported() && !isRequestField(field) && field.Tag.Get("json") != "-" {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns TRUE if the field is bound from route params, query params or headers
+func isRequestField(field reflect.StructField) bool {
+	return field.Tag.Get("json") == "" && (field.Tag.Get("param") != "" || field.Tag.Get("query") != "" || field.Tag.Get("header") != "")
+}
+
+func jsonContent(schema *Schema) map[string]*OpenAPIMediaType {
+	return map[string]*OpenAPIMediaType{"application/json": {Schema: schema}}
+}
+
+func (g *schemaGenerator) operation(info RouteInfo, route *Route) *OpenAPIOperation {
+	doc := RouteDoc{}
+	if route.doc != nil {
+		doc = *route.doc
+	}
+
+	operation := &OpenAPIOperation{
+		OperationID: doc.OperationID,
+		Summary:     doc.Summary,
+		Description: doc.Description,
+		Tags:        doc.Tags,
+		Deprecated:  doc.Deprecated,
+		Parameters:  pathParameters(route, info.Path, doc.Params),
+		Responses:   make(map[string]*OpenAPIResponse),
+	}
+	if operation.OperationID == "" {
+		operation.OperationID = info.Name
+	}
+
+	requestType, responseType, queryType := route.requestType, route.responseType, route.requestType
+	if doc.Request != nil {
+		requestType = reflect.TypeOf(doc.Request)
+	}
+	if doc.Response != nil {
+		responseType = reflect.TypeOf(doc.Response)
+	}
+	if doc.Query != nil {
+		queryType = reflect.TypeOf(doc.Query)
+	}
+	operation.Parameters = append(operation.Parameters, g.taggedParameters(queryType, "query", "query", doc.Params)...)
+	operation.Parameters = append(operation.Parameters, g.taggedParameters(route.requestType, "header", "header", doc.Params)...)
+
+	inferredBody := requestType != nil && hasBodyFields(requestType) && info.Method != MethodGet && info.Method != MethodHead
+	if doc.Request != nil || inferredBody {
+		operation.RequestBody = &OpenAPIRequestBody{
+			Required: true,
+			Content:  jsonContent(g.schema(requestType)),
+		}
+	}
+
+	status := doc.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	response := &OpenAPIResponse{Description: http.StatusText(status)}
+	if responseType != nil {
+		response.Content = jsonContent(g.successSchema(g.schema(responseType)))
+	}
+	operation.Responses[strconv.Itoa(status)] = response
+	operation.Responses["default"] = &OpenAPIResponse{
+		Description: "Error",
+		Content:     jsonContent(g.errorSchema()),
+	}
+	return operation
+}
+
+// Generates OpenAPI document of all routes including routes of mounted routers.
+//
+// Request and response bodies are described by Go types given in RouteDoc or types of handlers registered by HandleJSON, the response body is described inside
+// of Envelope of the router. Named struct types are stored in components.
+// Mounted handlers which are not SimpleRouter and hidden routes are skipped.
+func (sr SimpleRouter) OpenAPI(info OpenAPIInfo) *OpenAPIDocument {
+	generator := newSchemaGenerator()
+	generator.envelope = sr.Envelope
+	if generator.envelope == nil {
+		generator.envelope = DefaultEnvelope{}
+	}
+	document := &OpenAPIDocument{
+		OpenAPI: OpenAPIVersion,
+		Info:    info,
+		Paths:   make(map[string]*OpenAPIPathItem),
+	}
+
+	sr.walk("", "", len(sr.middlewares), func(info RouteInfo, route *Route) error {
+		if route.doc != nil && route.doc.Hidden {
+			return nil
+		}
+		path := openAPIPath(info.Path)
+		item := document.Paths[path]
+		if item == nil {
+			item = &OpenAPIPathItem{}
+		}
+		operation := item.operation(info.Method)
+		if operation == nil {
+			return nil
+		}
+		*operation = generator.operation(info, route)
+		document.Paths[path] = item
+		return nil
+	})
+
+	if len(generator.schemas) > 0 {
+		document.Components = &OpenAPIComponents{Schemas: generator.schemas}
+	}
+	return document
+}
+
+// Serves OpenAPI document of the router by GET requests to the route, the route itself is hidden in the document.
+//
+// The document is generated on every request, so it describes routes which are added later too.
+func (sr SimpleRouter) ServeOpenAPI(route string, info OpenAPIInfo) RouterMethods {
+	return sr.Get(route, func(ctx *Context) {
+		if err := ctx.writeJSON(http.StatusOK, sr.OpenAPI(info)); err != nil {
+			ctx.Error(NewHTTPError(http.StatusInternalServerError, MessageInternalServerError).Wrap(err))
+		}
+	}).Describe(RouteDoc{Hidden: true})
+}
@@ -0,0 +1,219 @@
+package rou
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type docAddress struct {
+	City string `json:"city"`
+}
+
+type docUser struct {
+	ID        int               `json:"id"`
+	Name      string            `json:"name" doc:"Full name"`
+	Email     *string           `json:"email,omitempty"`
+	Tags      []string          `json:"tags,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Address   *docAddress       `json:"address,omitempty"`
+	Billing   *docAddress       `json:"billing"`
+	CreatedAt time.Time         `json:"created_at"`
+	secret    string
+	Ignored   string `json:"-"`
+}
+
+type docUsersQuery struct {
+	Limit  int    `query:"limit" doc:"Max number of users"`
+	Search string `query:"q"`
+}
+
+func TestSchemaGenerator(t *testing.T) {
+	generator := newSchemaGenerator()
+	schema := generator.schema(reflect.TypeOf(docUser{}))
+	if schema.Ref != "#/components/schemas/docUser" {
+		t.Fatalf("expected reference to component, got %+v", schema)
+	}
+
+	user := generator.schemas["docUser"]
+	if user == nil {
+		t.Fatal("expected schema of docUser in components")
+	}
+	if !reflect.DeepEqual(user.Required, []string{"id", "name", "billing", "created_at"}) {
+		t.Errorf("Got required - %v", user.Required)
+	}
+
+	tests := []struct {
+		property string
+		want     *Schema
+	}{
+		{"id", &Schema{Type: SchemaType{"integer"}, Format: "int64"}},
+		{"name", &Schema{Type: SchemaType{"string"}, Description: "Full name"}},
+		{"email", &Schema{Type: SchemaType{"string", "null"}}},
+		{"tags", &Schema{Type: SchemaType{"array"}, Items: &Schema{Type: SchemaType{"string"}}}},
+		{"labels", &Schema{Type: SchemaType{"object"}, AdditionalProperties: &AdditionalProperties{Allowed: true, Schema: &Schema{Type: SchemaType{"string"}}}}},
+		{"address", &Schema{AnyOf: []*Schema{{Ref: "#/components/schemas/docAddress"}, {Type: SchemaType{"null"}}}}},
+		{"billing", &Schema{AnyOf: []*Schema{{Ref: "#/components/schemas/docAddress"}, {Type: SchemaType{"null"}}}}},
+		{"created_at", &Schema{Type: SchemaType{"string"}, Format: "date-time"}},
+	}
+	for _, test := range tests {
+		if got := user.Properties[test.property]; !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: Got - %+v, want - %+v", test.property, got, test.want)
+		}
+	}
+	if len(user.Properties) != len(tests) {
+		t.Errorf("expected %d properties, got %d", len(tests), len(user.Properties))
+	}
+}
+
+func TestOpenAPI(t *testing.T) {
+	router := NewRouter()
+	router.RegisterConstraint("slug", func(value string) bool { return value != "" })
+	router.Get("/users", listUsersHandler).Describe(RouteDoc{
+		Summary:  "List users",
+		Tags:     []string{"users"},
+		Query:    docUsersQuery{},
+		Response: []docUser{},
+		Params:   map[string]string{"q": "Search phrase"},
+	}).Name("users")
+	router.Post("/users", listUsersHandler).Describe(RouteDoc{
+		OperationID: "createUser",
+		Request:     docUser{},
+		Response:    docUser{},
+		Status:      http.StatusCreated,
+	})
+	router.Get("/users/:id<int>/posts/:slug<slug>/:code<[a-z]{2}>", listUsersHandler)
+	router.Get("/files/:id<uuid>/*path", listUsersHandler)
+	router.Get("/hidden", listUsersHandler).Describe(RouteDoc{Hidden: true})
+	router.Mount("/debug", http.NotFoundHandler())
+
+	subRouter := NewRouter()
+	subRouter.Delete("/invoices/:id<uint>", listUsersHandler)
+	router.Mount("/billing", subRouter)
+
+	document := router.OpenAPI(OpenAPIInfo{Title: "Users", Version: "1.0.0"})
+	if document.OpenAPI != OpenAPIVersion {
+		t.Errorf("Got version - %q", document.OpenAPI)
+	}
+
+	var paths []string
+	for path := range document.Paths {
+		paths = append(paths, path)
+	}
+	wantPaths := map[string][]string{
+		"/users":                          {MethodGet, MethodPost},
+		"/users/{id}/posts/{slug}/{code}": {MethodGet},
+		"/files/{id}/{path}":              {MethodGet},
+		"/billing/invoices/{id}":          {MethodDelete},
+	}
+	if len(document.Paths) != len(wantPaths) {
+		t.Fatalf("Got paths - %v", paths)
+	}
+	for path, methods := range wantPaths {
+		operations := document.Paths[path].Operations()
+		if len(operations) != len(methods) {
+			t.Errorf("Got operations of %s - %v", path, operations)
+		}
+		for _, method := range methods {
+			if operations[method] == nil {
+				t.Errorf("expected operation %s %s", method, path)
+			}
+		}
+	}
+
+	list := document.Paths["/users"].Get
+	if list.OperationID != "users" || list.Summary != "List users" || !reflect.DeepEqual(list.Tags, []string{"users"}) {
+		t.Errorf("Got operation - %+v", list)
+	}
+	wantQuery := []*OpenAPIParameter{
+		{Name: "limit", In: "query", Description: "Max number of users", Schema: &Schema{Type: SchemaType{"integer"}, Format: "int64"}},
+		{Name: "q", In: "query", Description: "Search phrase", Schema: &Schema{Type: SchemaType{"string"}}},
+	}
+	if !reflect.DeepEqual(list.Parameters, wantQuery) {
+		t.Errorf("Got query params - %+v", list.Parameters)
+	}
+	body := list.Responses["200"].Content["application/json"].Schema.Properties["body"]
+	if !reflect.DeepEqual(body, &Schema{Type: SchemaType{"array"}, Items: &Schema{Ref: "#/components/schemas/docUser"}}) {
+		t.Errorf("Got response body - %+v", body)
+	}
+	if list.Responses["default"] == nil {
+		t.Error("expected default error response")
+	}
+
+	create := document.Paths["/users"].Post
+	if create.OperationID != "createUser" || create.Responses["201"] == nil || create.RequestBody == nil {
+		t.Errorf("Got operation - %+v", create)
+	}
+
+	wantParams := []*OpenAPIParameter{
+		{Name: "id", In: "path", Required: true, Schema: &Schema{Type: SchemaType{"integer"}}},
+		{Name: "slug", In: "path", Required: true, Schema: &Schema{Type: SchemaType{"string"}}},
+		{Name: "code", In: "path", Required: true, Schema: &Schema{Type: SchemaType{"string"}, Pattern: "^(?:[a-z]{2})$"}},
+	}
+	if got := document.Paths["/users/{id}/posts/{slug}/{code}"].Get.Parameters; !reflect.DeepEqual(got, wantParams) {
+		t.Errorf("Got path params - %+v", got)
+	}
+	if got := document.Paths["/files/{id}/{path}"].Get.Parameters[0].Schema; got.Format != "uuid" {
+		t.Errorf("Got uuid param schema - %+v", got)
+	}
+	if got := document.Paths["/billing/invoices/{id}"].Delete.Parameters[0].Schema; *got.Minimum != 0 {
+		t.Errorf("Got uint param schema - %+v", got)
+	}
+
+	for _, name := range []string{"docUser", "docAddress", "ErrorObject"} {
+		if document.Components.Schemas[name] == nil {
+			t.Errorf("expected component %q", name)
+		}
+	}
+}
+
+func TestServeOpenAPI(t *testing.T) {
+	router := NewRouter()
+	router.ServeOpenAPI("/openapi.json", OpenAPIInfo{Title: "Users", Version: "1.0.0"})
+	router.Get("/users/:id", listUsersHandler)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Got status - %d", w.Code)
+	}
+
+	var document map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &document); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	paths := document["paths"].(map[string]any)
+	if _, ok := paths["/users/{id}"]; !ok || len(paths) != 1 {
+		t.Errorf("Got paths - %v", paths)
+	}
+	if document["openapi"] != OpenAPIVersion {
+		t.Errorf("Got version - %v", document["openapi"])
+	}
+}
+
+func TestSchemaJSON(t *testing.T) {
+	schema := &Schema{
+		Type:                 SchemaType{"object"},
+		AdditionalProperties: &AdditionalProperties{},
+		Properties:           map[string]*Schema{"name": {Type: SchemaType{"string", "null"}}},
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type":"object","properties":{"name":{"type":["string","null"]}},"additionalProperties":false}`
+	if string(data) != want {
+		t.Errorf("Got - %s, want - %s", data, want)
+	}
+
+	var decoded Schema
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&decoded, schema) {
+		t.Errorf("Got decoded - %+v", decoded)
+	}
+}
@@ -0,0 +1,222 @@
+package rou
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Route param or query param of request which is converted to other types.
+//
+// Methods without default value return error if the param is not given or empty,
+// methods with suffix "Or" return the default value in this case.
+// Errors are HTTPError with status 400, MessageRequestIsNotValid and SchemaViolation in details.
+type Param struct {
+	// Part of request: "path" or "query"
+	In     string
+	Name   string
+	values []string
+}
+
+// Returns route param by name
+//
+//	id, err := ctx.RouterParam("id").Int()
+func (c Context) RouterParam(name string) Param {
+	param := Param{In: "path", Name: name}
+	if c.RouterParams().Has(name) {
+		param.values = []string{c.RouterParams().Get(name)}
+	}
+	return param
+}
+
+// Returns query param by name, params with several values can be read by Strings and Ints
+//
+//	limit, err := ctx.QueryParam("limit").IntOr(20)
+func (c Context) QueryParam(name string) Param {
+	return Param{In: "query", Name: name, values: c.Params()[name]}
+}
+
+// Returns the first value of the param or empty string
+func (p Param) Value() string {
+	if len(p.values) == 0 {
+		return ""
+	}
+	return p.values[0]
+}
+
+// Returns TRUE if the param is not given or empty
+func (p Param) Missing() bool {
+	return p.Value() == ""
+}
+
+// Returns HTTPError with violation of the param
+func (p Param) invalid(format string, args ...any) error {
+	return NewHTTPError(http.StatusBadRequest, MessageRequestIsNotValid).WithDetails([]SchemaViolation{{
+		In:      p.In,
+		Path:    p.Name,
+		Message: fmt.Sprintf(format, args...),
+	}})
+}
+
+// Returns value of the param converted by parse or error if it is missing or can not be converted
+func convertParam[T any](p Param, expected string, parse func(string) (T, error)) (T, error) {
+	var result T
+	if p.Missing() {
+		return result, p.invalid("value is required")
+	}
+	result, err := parse(p.Value())
+	if err != nil {
+		return result, p.invalid("expected %s, got %q", expected, p.Value())
+	}
+	return result, nil
+}
+
+// Returns the default value if the param is missing, otherwise returns result of convert
+func paramOr[T any](p Param, defaultValue T, convert func() (T, error)) (T, error) {
+	if p.Missing() {
+		return defaultValue, nil
+	}
+	return convert()
+}
+
+// Returns value of the param or error if it is missing
+func (p Param) Required() (string, error) {
+	return convertParam(p, "string", func(value string) (string, error) { return value, nil })
+}
+
+// Returns value of the param or the default value if it is missing or empty
+func (p Param) ValueOr(defaultValue string) string {
+	if p.Missing() {
+		return defaultValue
+	}
+	return p.Value()
+}
+
+// Returns value converted to int or error if it is missing or not integer
+func (p Param) Int() (int, error) {
+	return convertParam(p, "integer", strconv.Atoi)
+}
+
+// Returns value converted to int or the default value if it is missing, invalid value returns error instead of the default value
+func (p Param) IntOr(defaultValue int) (int, error) {
+	return paramOr(p, defaultValue, p.Int)
+}
+
+// Returns value converted to int64 or error if it is missing or not integer
+func (p Param) Int64() (int64, error) {
+	return convertParam(p, "integer", func(value string) (int64, error) {
+		return strconv.ParseInt(value, 10, 64)
+	})
+}
+
+// Returns value converted to int64 or the default value if it is missing, invalid value returns error instead of the default value
+func (p Param) Int64Or(defaultValue int64) (int64, error) {
+	return paramOr(p, defaultValue, p.Int64)
+}
+
+// Returns value converted to uint or error if it is missing or not unsigned integer
+func (p Param) Uint() (uint, error) {
+	return convertParam(p, "unsigned integer", func(value string) (uint, error) {
+		parsed, err := strconv.ParseUint(value, 10, strconv.IntSize)
+		return uint(parsed), err
+	})
+}
+
+// Returns value converted to uint or the default value if it is missing, invalid value returns error instead of the default value
+func (p Param) UintOr(defaultValue uint) (uint, error) {
+	return paramOr(p, defaultValue, p.Uint)
+}
+
+// Returns value converted to float64 or error if it is missing or not number
+func (p Param) Float64() (float64, error) {
+	return convertParam(p, "number", func(value string) (float64, error) {
+		return strconv.ParseFloat(value, 64)
+	})
+}
+
+// Returns value converted to float64 or the default value if it is missing, invalid value returns error instead of the default value
+func (p Param) Float64Or(defaultValue float64) (float64, error) {
+	return paramOr(p, defaultValue, p.Float64)
+}
+
+// Converts value by strconv.ParseBool: "1", "t", "true", "0", "f", "false" and so on, returns error if it is missing or invalid
+func (p Param) Bool() (bool, error) {
+	return convertParam(p, "boolean", strconv.ParseBool)
+}
+
+// Returns value converted like Bool or the default value if it is missing, invalid value returns error instead of the default value
+func (p Param) BoolOr(defaultValue bool) (bool, error) {
+	return paramOr(p, defaultValue, p.Bool)
+}
+
+// Parses value by the layout, e.g. time.RFC3339 or "2006-01-02", returns error if it is missing or invalid
+func (p Param) Time(layout string) (time.Time, error) {
+	return convertParam(p, "time in format "+layout, func(value string) (time.Time, error) {
+		return time.Parse(layout, value)
+	})
+}
+
+// Returns value parsed by the layout or the default value if it is missing, invalid value returns error instead of the default value
+func (p Param) TimeOr(layout string, defaultValue time.Time) (time.Time, error) {
+	return paramOr(p, defaultValue, func() (time.Time, error) { return p.Time(layout) })
+}
+
+// Returns value if it is UUID in canonical form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx, otherwise returns error
+func (p Param) UUID() (string, error) {
+	return convertParam(p, "UUID", func(value string) (string, error) {
+		if !isUUID(value) {
+			return "", fmt.Errorf("invalid UUID")
+		}
+		return value, nil
+	})
+}
+
+// Returns UUID value or the default value if it is missing, invalid value returns error instead of the default value
+func (p Param) UUIDOr(defaultValue string) (string, error) {
+	return paramOr(p, defaultValue, p.UUID)
+}
+
+// Returns items of comma-separated value, values of query param given several times are joined:
+// "?tag=a,b&tag=c" returns ["a", "b", "c"]
+func (p Param) Strings() ([]string, error) {
+	if p.Missing() {
+		return nil, p.invalid("value is required")
+	}
+	var items []string
+	for _, value := range p.values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				return nil, p.invalid("list %q has empty items", value)
+			}
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// Returns items like Strings or the default value if it is missing, list with empty items returns error instead of the default value
+func (p Param) StringsOr(defaultValue []string) ([]string, error) {
+	return paramOr(p, defaultValue, p.Strings)
+}
+
+// Returns integer items of comma-separated value like Strings or error if it is missing or items are not integers
+func (p Param) Ints() ([]int, error) {
+	items, err := p.Strings()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]int, len(items))
+	for i, item := range items {
+		if result[i], err = strconv.Atoi(item); err != nil {
+			return nil, p.invalid("expected list of integers, got %q", item)
+		}
+	}
+	return result, nil
+}
+
+// Returns integer items like Ints or the default value if it is missing, invalid items return error instead of the default value
+func (p Param) IntsOr(defaultValue []int) ([]int, error) {
+	return paramOr(p, defaultValue, p.Ints)
+}
@@ -0,0 +1,129 @@
+package rou
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func paramContext(target string, params map[string]string) *Context {
+	ctx := NewRouter().createContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	for name, value := range params {
+		ctx.RouterParams().Set(name, value)
+	}
+	return ctx
+}
+
+func assertParamError(t *testing.T, err error, want SchemaViolation) {
+	t.Helper()
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("expected HTTPError, got %v", err)
+	}
+	if httpErr.Status != http.StatusBadRequest || httpErr.Message != MessageRequestIsNotValid {
+		t.Errorf("Got - %d %q", httpErr.Status, httpErr.Message)
+	}
+	if details := []SchemaViolation{want}; !reflect.DeepEqual(httpErr.Details, details) {
+		t.Errorf("Got details - %+v, want - %+v", httpErr.Details, details)
+	}
+}
+
+func TestRouterParam(t *testing.T) {
+	ctx := paramContext("/", map[string]string{
+		"id":    "42",
+		"uid":   "7d444840-9dc0-11d1-b245-5ffdce74fad2",
+		"price": "9.5",
+		"day":   "2024-02-29",
+		"flag":  "true",
+		"bad":   "abc",
+	})
+
+	if id, err := ctx.RouterParam("id").Int(); err != nil || id != 42 {
+		t.Errorf("Int: got %d, %v", id, err)
+	}
+	if id, err := ctx.RouterParam("id").Int64(); err != nil || id != 42 {
+		t.Errorf("Int64: got %d, %v", id, err)
+	}
+	if id, err := ctx.RouterParam("id").Uint(); err != nil || id != 42 {
+		t.Errorf("Uint: got %d, %v", id, err)
+	}
+	if price, err := ctx.RouterParam("price").Float64(); err != nil || price != 9.5 {
+		t.Errorf("Float64: got %v, %v", price, err)
+	}
+	if flag, err := ctx.RouterParam("flag").Bool(); err != nil || !flag {
+		t.Errorf("Bool: got %v, %v", flag, err)
+	}
+	if day, err := ctx.RouterParam("day").Time("2006-01-02"); err != nil || !day.Equal(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Time: got %v, %v", day, err)
+	}
+	if uid, err := ctx.RouterParam("uid").UUID(); err != nil || uid != "7d444840-9dc0-11d1-b245-5ffdce74fad2" {
+		t.Errorf("UUID: got %q, %v", uid, err)
+	}
+	if value, err := ctx.RouterParam("bad").Required(); err != nil || value != "abc" {
+		t.Errorf("Required: got %q, %v", value, err)
+	}
+
+	_, err := ctx.RouterParam("bad").Int()
+	assertParamError(t, err, SchemaViolation{In: "path", Path: "bad", Message: `expected integer, got "abc"`})
+	_, err = ctx.RouterParam("bad").UUID()
+	assertParamError(t, err, SchemaViolation{In: "path", Path: "bad", Message: `expected UUID, got "abc"`})
+	_, err = ctx.RouterParam("missing").Int()
+	assertParamError(t, err, SchemaViolation{In: "path", Path: "missing", Message: "value is required"})
+	_, err = ctx.RouterParam("id").Time(time.RFC3339)
+	assertParamError(t, err, SchemaViolation{In: "path", Path: "id", Message: `expected time in format ` + time.RFC3339 + `, got "42"`})
+}
+
+func TestQueryParam(t *testing.T) {
+	ctx := paramContext("/?limit=&page=3&tag=a,b&tag=c&ids=1,2,3&bad=1,,2&exact=maybe", nil)
+
+	if limit, err := ctx.QueryParam("limit").IntOr(20); err != nil || limit != 20 {
+		t.Errorf("IntOr of empty value: got %d, %v", limit, err)
+	}
+	if page, err := ctx.QueryParam("page").IntOr(1); err != nil || page != 3 {
+		t.Errorf("IntOr: got %d, %v", page, err)
+	}
+	if sort := ctx.QueryParam("sort").ValueOr("name"); sort != "name" {
+		t.Errorf("ValueOr: got %q", sort)
+	}
+	if since, err := ctx.QueryParam("since").TimeOr(time.RFC3339, time.Unix(0, 0)); err != nil || !since.Equal(time.Unix(0, 0)) {
+		t.Errorf("TimeOr: got %v, %v", since, err)
+	}
+	if tags, err := ctx.QueryParam("tag").Strings(); err != nil || !reflect.DeepEqual(tags, []string{"a", "b", "c"}) {
+		t.Errorf("Strings: got %v, %v", tags, err)
+	}
+	if ids, err := ctx.QueryParam("ids").Ints(); err != nil || !reflect.DeepEqual(ids, []int{1, 2, 3}) {
+		t.Errorf("Ints: got %v, %v", ids, err)
+	}
+	if ids, err := ctx.QueryParam("other").IntsOr([]int{7}); err != nil || !reflect.DeepEqual(ids, []int{7}) {
+		t.Errorf("IntsOr: got %v, %v", ids, err)
+	}
+
+	_, err := ctx.QueryParam("exact").BoolOr(false)
+	assertParamError(t, err, SchemaViolation{In: "query", Path: "exact", Message: `expected boolean, got "maybe"`})
+	_, err = ctx.QueryParam("bad").Ints()
+	assertParamError(t, err, SchemaViolation{In: "query", Path: "bad", Message: `list "1,,2" has empty items`})
+	_, err = ctx.QueryParam("tag").Ints()
+	assertParamError(t, err, SchemaViolation{In: "query", Path: "tag", Message: `expected list of integers, got "a"`})
+}
+
+func TestParamErrorResponse(t *testing.T) {
+	router := NewRouter()
+	router.Get("/users/:id", WithError(func(ctx *Context) error {
+		id, err := ctx.RouterParam("id").Int()
+		if err != nil {
+			return err
+		}
+		ctx.SuccessJSONResponse(id)
+		return nil
+	}))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/abc", nil))
+	want := `{"error":{"message":"Request is not valid","code":400,"details":[{"in":"path","path":"id","message":"expected integer, got \"abc\""}]},"body":null}`
+	if w.Code != http.StatusBadRequest || w.Body.String() != want {
+		t.Errorf("Got - %d %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,95 @@
+package rou
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Defines how request path which differs from the registered route is handled
+type PathPolicy uint8
+
+const (
+	// Trailing slashes are ignored and route of clean path is served without redirect:
+	// "/users/" and "//users/../users" match route "/users"
+	PathTolerant PathPolicy = iota
+	// Request path should be clean and have trailing slash only if the route has it, otherwise 404 is returned
+	PathStrict
+	// Request is redirected to the clean path with trailing slash added or removed as it is in the route.
+	// GET and HEAD requests are redirected with status 301, other requests with status 308 to preserve method and body.
+	PathRedirect
+)
+
+// Returns TRUE if the last segment of the route matches the rest of path
+func isCatchAll(route string) bool {
+	key := routeKey(route)
+	return strings.HasPrefix(key[strings.LastIndexByte(key, '/')+1:], "*")
+}
+
+// Returns path with "." and ".." segments and duplicated slashes removed
+// and trailing slash kept or removed as it is in route
+func canonicalPath(requestPath string, route *Route) string {
+	trailingSlash := strings.HasSuffix(route.Path, "/")
+	if route.mount != nil || isCatchAll(route.Path) {
+		trailingSlash = strings.HasSuffix(requestPath, "/")
+	}
+
+	canonical := path.Clean("/" + requestPath)
+	if trailingSlash && canonical != "/" {
+		canonical += "/"
+	}
+	return canonical
+}
+
+// Finds route for the clean request path and checks path of request by policy.
+//
+// Returns redirect location if request should be redirected
+// or rejected as TRUE if the path is not allowed by policy.
+func (sr *SimpleRouter) lookupByPolicy(r *http.Request, requestPath string) (route *Route, values []string, redirect string, rejected bool) {
+	route, values = sr.Routes.lookup(r.Method, requestPath)
+	if route == nil && sr.PathPolicy != PathStrict {
+		route, values = sr.Routes.lookup(r.Method, path.Clean("/"+requestPath))
+	}
+	if sr.PathPolicy == PathTolerant {
+		return route, values, "", false
+	}
+	if route == nil {
+		other := sr.Routes.anyRoute(requestPath)
+		rejected = sr.PathPolicy == PathStrict && other != nil && canonicalPath(requestPath, other) != requestPath
+		return nil, nil, "", rejected
+	}
+
+	canonical := canonicalPath(requestPath, route)
+	if canonical == requestPath {
+		return route, values, "", false
+	}
+	if sr.PathPolicy == PathStrict {
+		return nil, nil, "", true
+	}
+
+	location := url.URL{Path: canonical, RawQuery: r.URL.RawQuery}
+	if sr.UseRawPath {
+		location.Path = decodeKeptEscapes(canonical)
+		location.RawPath = escapeSegments(canonical)
+	}
+	return nil, nil, location.String(), false
+}
+
+// Escapes every segment of the path decoded by decodePathKeepingSlashes
+func escapeSegments(decodedPath string) string {
+	segments := strings.Split(decodedPath, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(decodeKeptEscapes(segment))
+	}
+	return strings.Join(segments, "/")
+}
+
+// Redirects request to the location with status 301 for GET and HEAD requests and 308 for other ones
+func redirect(ctx *Context, location string) {
+	status := http.StatusPermanentRedirect
+	if method := ctx.Request().Method; method == MethodGet || method == MethodHead {
+		status = http.StatusMovedPermanently
+	}
+	http.Redirect(ctx.ResponseWriter(), ctx.Request(), location, status)
+}
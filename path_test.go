@@ -0,0 +1,74 @@
+package rou
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPathPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      PathPolicy
+		method      string
+		requestPath string
+		status      int
+		location    string
+	}{
+		{name: "tolerant trailing slash", policy: PathTolerant, method: http.MethodGet, requestPath: "/users/", status: http.StatusOK},
+		{name: "tolerant missing trailing slash", policy: PathTolerant, method: http.MethodGet, requestPath: "/posts", status: http.StatusOK},
+		{name: "tolerant cleans path", policy: PathTolerant, method: http.MethodGet, requestPath: "/posts/../users", status: http.StatusOK},
+		{name: "tolerant duplicated slashes", policy: PathTolerant, method: http.MethodGet, requestPath: "//users/../users", status: http.StatusOK},
+		{name: "tolerant unknown clean path", policy: PathTolerant, method: http.MethodGet, requestPath: "/users/../files", status: http.StatusNotFound},
+
+		{name: "strict exact path", policy: PathStrict, method: http.MethodGet, requestPath: "/users", status: http.StatusOK},
+		{name: "strict exact path with trailing slash", policy: PathStrict, method: http.MethodGet, requestPath: "/posts/", status: http.StatusOK},
+		{name: "strict trailing slash", policy: PathStrict, method: http.MethodGet, requestPath: "/users/", status: http.StatusNotFound},
+		{name: "strict missing trailing slash", policy: PathStrict, method: http.MethodGet, requestPath: "/posts", status: http.StatusNotFound},
+		{name: "strict not clean path", policy: PathStrict, method: http.MethodGet, requestPath: "//users", status: http.StatusNotFound},
+		{name: "strict other method", policy: PathStrict, method: http.MethodPut, requestPath: "/users/", status: http.StatusNotFound},
+
+		{name: "redirect removes trailing slash", policy: PathRedirect, method: http.MethodGet, requestPath: "/users/?page=2", status: http.StatusMovedPermanently, location: "/users?page=2"},
+		{name: "redirect adds trailing slash", policy: PathRedirect, method: http.MethodGet, requestPath: "/posts", status: http.StatusMovedPermanently, location: "/posts/"},
+		{name: "redirect cleans path", policy: PathRedirect, method: http.MethodGet, requestPath: "/posts/../users/./10", status: http.StatusMovedPermanently, location: "/users/10"},
+		{name: "redirect duplicated slashes", policy: PathRedirect, method: http.MethodGet, requestPath: "//users//10", status: http.StatusMovedPermanently, location: "/users/10"},
+		{name: "redirect preserves method", policy: PathRedirect, method: http.MethodPost, requestPath: "/users/", status: http.StatusPermanentRedirect, location: "/users"},
+		{name: "redirect catch-all keeps trailing slash", policy: PathRedirect, method: http.MethodGet, requestPath: "/static/css/", status: http.StatusOK},
+		{name: "redirect escapes location", policy: PathRedirect, method: http.MethodGet, requestPath: "/users/a b/", status: http.StatusMovedPermanently, location: "/users/a%20b"},
+		{name: "redirect canonical path", policy: PathRedirect, method: http.MethodGet, requestPath: "/users/10", status: http.StatusOK},
+	}
+
+	fakeHandler := func(ctx *Context) {}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := NewRouter()
+			router.PathPolicy = test.policy
+			router.Get("/users", fakeHandler)
+			router.Post("/users", fakeHandler)
+			router.Get("/users/:id", fakeHandler)
+			router.Get("/posts/", fakeHandler)
+			router.Get("/static/*filepath", fakeHandler)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(test.method, "/", nil)
+			r.URL.Path, r.URL.RawQuery = splitQuery(test.requestPath)
+			router.ServeHTTP(w, r)
+
+			if w.Code != test.status {
+				t.Errorf("expected status %d, got %d", test.status, w.Code)
+			}
+			if w.Header().Get("Location") != test.location {
+				t.Errorf("wrong location. Got - %q, want - %q", w.Header().Get("Location"), test.location)
+			}
+		})
+	}
+}
+
+func splitQuery(requestPath string) (string, string) {
+	for i := 0; i < len(requestPath); i++ {
+		if requestPath[i] == '?' {
+			return requestPath[:i], requestPath[i+1:]
+		}
+	}
+	return requestPath, ""
+}
@@ -0,0 +1,229 @@
+package rou
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+)
+
+const ContentTypeProblemJSON = "application/problem+json"
+
+// Members of problem details which can not be used as extensions
+var problemMembers = map[string]bool{"type": true, "title": true, "status": true, "detail": true, "instance": true}
+
+// Problem details of error response defined by RFC 9457, it is written as "application/problem+json".
+//
+// Problem can be returned as error from handlers, it is rendered as it is by ProblemErrorHandler.
+type Problem struct {
+	// URI reference which identifies the problem type, "about:blank" is assumed if it is empty
+	Type string
+	// Short summary of the problem type
+	Title  string
+	Status int
+	// Explanation of this occurrence of the problem
+	Detail string
+	// URI reference which identifies this occurrence of the problem
+	Instance string
+	// Additional members, members with names of standard members are ignored
+	Extensions map[string]any
+}
+
+// Creates Problem with status and title
+func NewProblem(status int, title string) *Problem {
+	return &Problem{Status: status, Title: title}
+}
+
+func (p *Problem) WithType(problemType string) *Problem {
+	p.Type = problemType
+	return p
+}
+
+func (p *Problem) WithDetail(detail string) *Problem {
+	p.Detail = detail
+	return p
+}
+
+func (p *Problem) WithInstance(instance string) *Problem {
+	p.Instance = instance
+	return p
+}
+
+// Sets extension member of the problem
+func (p *Problem) With(name string, value any) *Problem {
+	if p.Extensions == nil {
+		p.Extensions = make(map[string]any)
+	}
+	p.Extensions[name] = value
+	return p
+}
+
+func (p *Problem) Error() string {
+	if p.Detail != "" {
+		return p.Title + ": " + p.Detail
+	}
+	return p.Title
+}
+
+// Returns copy of the problem with own extensions
+func (p *Problem) clone() *Problem {
+	clone := *p
+	if p.Extensions != nil {
+		clone.Extensions = make(map[string]any, len(p.Extensions))
+		for name, value := range p.Extensions {
+			clone.Extensions[name] = value
+		}
+	}
+	return &clone
+}
+
+// Writes standard members first and extensions sorted by name
+func (p Problem) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+	write := func(name string, value any) error {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		if buffer.Len() > 1 {
+			buffer.WriteByte(',')
+		}
+		key, _ := json.Marshal(name)
+		buffer.Write(key)
+		buffer.WriteByte(':')
+		buffer.Write(data)
+		return nil
+	}
+
+	for _, member := range []struct {
+		name  string
+		value any
+		empty bool
+	}{
+		{"type", p.Type, p.Type == ""},
+		{"title", p.Title, p.Title == ""},
+		{"status", p.Status, p.Status == 0},
+		{"detail", p.Detail, p.Detail == ""},
+		{"instance", p.Instance, p.Instance == ""},
+	} {
+		if !member.empty {
+			write(member.name, member.value)
+		}
+	}
+
+	names := make([]string, 0, len(p.Extensions))
+	for name := range p.Extensions {
+		if !problemMembers[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := write(name, p.Extensions[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+// Reads standard members to fields and other members to extensions
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	*p = Problem{}
+	targets := map[string]any{"type": &p.Type, "title": &p.Title, "status": &p.Status, "detail": &p.Detail, "instance": &p.Instance}
+	for name, raw := range members {
+		if target, ok := targets[name]; ok {
+			if err := json.Unmarshal(raw, target); err != nil {
+				return err
+			}
+			continue
+		}
+		var value any
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return err
+		}
+		p.With(name, value)
+	}
+	return nil
+}
+
+// Converts error to Problem, returns nil if the error is not supported
+type ProblemMapper func(err error) *Problem
+
+// Returns ProblemMapper which converts errors matching target by errors.Is to copy of the problem
+//
+//	router.MapProblem(rou.ErrorProblem(sql.ErrNoRows, rou.NewProblem(http.StatusNotFound, "Not found")))
+func ErrorProblem(target error, problem *Problem) ProblemMapper {
+	return func(err error) *Problem {
+		if errors.Is(err, target) {
+			return problem.clone()
+		}
+		return nil
+	}
+}
+
+// Adds mapper which is used by ProblemErrorHandler to convert errors to Problem, mappers are called in order of adding
+func (sr *SimpleRouter) MapProblem(mapper ProblemMapper) {
+	sr.problemMappers = append(sr.problemMappers, mapper)
+}
+
+// Converts error to Problem: Problem from the chain of err is used as it is, then mappers of the router are called,
+// otherwise Problem is made of HTTPError returned by AsHTTPError
+func (c Context) problem(err error) *Problem {
+	var problem *Problem
+	if errors.As(err, &problem) {
+		return problem
+	}
+
+	if c.router != nil {
+		for _, mapper := range c.router.problemMappers {
+			if problem := mapper(err); problem != nil {
+				return problem
+			}
+		}
+	}
+
+	httpErr := AsHTTPError(err)
+	problem = NewProblem(httpErr.Status, httpErr.Message)
+	if httpErr.Code != 0 && httpErr.Code != httpErr.Status {
+		problem.With("code", httpErr.Code)
+	}
+	if httpErr.Details != nil {
+		problem.With("errors", httpErr.Details)
+	}
+	return problem
+}
+
+// Writes problem as "application/problem+json" with its status, status 500 is used if it is not set.
+// Problem with extensions which can not be encoded is replaced by problem with status 500.
+func (c *Context) Problem(problem *Problem) {
+	if problem.Status == 0 {
+		problem = problem.clone()
+		problem.Status = http.StatusInternalServerError
+	}
+	data, err := json.Marshal(problem)
+	if err != nil {
+		problem = NewProblem(http.StatusInternalServerError, MessageInternalServerError)
+		data, _ = json.Marshal(problem)
+	}
+	c.ResponseWriter().Header().Set("Content-Type", ContentTypeProblemJSON)
+	c.ResponseWriter().WriteHeader(problem.Status)
+	c.ResponseWriter().Write(data)
+}
+
+// Renders error as Problem, errors are converted by Problem from the chain of err, mappers added by MapProblem
+// or HTTPError: its message is title, code is "code" extension if it differs from status and details are "errors" extension.
+// Built-in errors of router like 404 and 405 are rendered as Problem too if it is ErrorHandler of the router.
+//
+//	router.ErrorHandler = rou.ProblemErrorHandler
+func ProblemErrorHandler(ctx *Context, err error) {
+	ctx.Problem(ctx.problem(err))
+}
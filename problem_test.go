@@ -0,0 +1,160 @@
+package rou
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProblemJSON(t *testing.T) {
+	problem := NewProblem(http.StatusForbidden, "Insufficient funds").
+		WithType("https://example.com/problems/funds").
+		WithDetail("Balance is 30").
+		WithInstance("/accounts/1").
+		With("balance", 30).
+		With("accounts", []string{"/accounts/1"}).
+		With("status", 200)
+
+	data, err := json.Marshal(problem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type":"https://example.com/problems/funds","title":"Insufficient funds","status":403,"detail":"Balance is 30","instance":"/accounts/1","accounts":["/accounts/1"],"balance":30}`
+	if string(data) != want {
+		t.Errorf("Got - %s, want - %s", data, want)
+	}
+
+	var decoded Problem
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Status != http.StatusForbidden || decoded.Type != problem.Type || decoded.Extensions["balance"] != float64(30) || len(decoded.Extensions) != 2 {
+		t.Errorf("Got - %+v", decoded)
+	}
+
+	if _, err := json.Marshal(NewProblem(http.StatusBadRequest, "Bad").With("func", func() {})); err == nil {
+		t.Error("expected error of extension which can not be encoded")
+	}
+}
+
+func TestProblemErrorHandler(t *testing.T) {
+	errNotFound := errors.New("record not found")
+
+	router := NewRouter()
+	router.ErrorHandler = ProblemErrorHandler
+	router.MapProblem(ErrorProblem(errNotFound, NewProblem(http.StatusNotFound, "Record not found").WithType("https://example.com/not-found")))
+	router.Get("/problem", WithError(func(ctx *Context) error {
+		return fmt.Errorf("transfer: %w", NewProblem(http.StatusForbidden, "Insufficient funds").With("balance", 30))
+	}))
+	router.Get("/mapped", WithError(func(ctx *Context) error {
+		return fmt.Errorf("find: %w", errNotFound)
+	}))
+	router.Get("/http", WithError(func(ctx *Context) error {
+		return NewHTTPError(http.StatusConflict, "User exists").WithCode(1001).WithDetails([]string{"email"})
+	}))
+	router.Get("/unknown", WithError(func(ctx *Context) error {
+		return errors.New("database is down")
+	}))
+	router.Get("/users/:id<int>", func(ctx *Context) {})
+	router.Post("/bind", func(ctx *Context) {
+		var body struct {
+			Name string `json:"name" validate:"required"`
+		}
+		if err := ctx.Bind(&body); err != nil {
+			ctx.Error(err)
+		}
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		status int
+		want   string
+	}{
+		{
+			name: "problem", method: http.MethodGet, target: "/problem",
+			status: http.StatusForbidden,
+			want:   `{"title":"Insufficient funds","status":403,"balance":30}`,
+		},
+		{
+			name: "mapped error", method: http.MethodGet, target: "/mapped",
+			status: http.StatusNotFound,
+			want:   `{"type":"https://example.com/not-found","title":"Record not found","status":404}`,
+		},
+		{
+			name: "HTTP error", method: http.MethodGet, target: "/http",
+			status: http.StatusConflict,
+			want:   `{"title":"User exists","status":409,"code":1001,"errors":["email"]}`,
+		},
+		{
+			name: "unknown error", method: http.MethodGet, target: "/unknown",
+			status: http.StatusInternalServerError,
+			want:   `{"title":"Internal server error","status":500}`,
+		},
+		{
+			name: "not found", method: http.MethodGet, target: "/users/abc",
+			status: http.StatusNotFound,
+			want:   `{"title":"Page not found","status":404}`,
+		},
+		{
+			name: "method not allowed", method: http.MethodDelete, target: "/users/1",
+			status: http.StatusMethodNotAllowed,
+			want:   `{"title":"Method not allowed","status":405}`,
+		},
+		{
+			name: "bad request", method: http.MethodPost, target: "/bind",
+			status: http.StatusBadRequest,
+			want:   `{"title":"Request body is not valid","status":400,"errors":[{"path":"/name","rule":"required","message":"value is required"}]}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest(test.method, test.target, strings.NewReader(`{}`))
+			r.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+
+			if w.Code != test.status || w.Body.String() != test.want {
+				t.Errorf("Got - %d %s, want - %d %s", w.Code, w.Body.String(), test.status, test.want)
+			}
+			if contentType := w.Header().Get("Content-Type"); contentType != ContentTypeProblemJSON {
+				t.Errorf("Got content type - %q", contentType)
+			}
+		})
+	}
+}
+
+func TestErrorProblemCopy(t *testing.T) {
+	errTarget := errors.New("target")
+	mapper := ErrorProblem(errTarget, NewProblem(http.StatusNotFound, "Not found").With("a", 1))
+
+	first := mapper(errTarget).With("b", 2)
+	second := mapper(errTarget)
+	if want := map[string]any{"a": 1}; !reflect.DeepEqual(second.Extensions, want) {
+		t.Errorf("Got - %v, want - %v", second.Extensions, want)
+	}
+	if first == second || mapper(errors.New("other")) != nil {
+		t.Error("expected copy of problem for matching errors only")
+	}
+}
+
+func TestProblemWithDefaultErrorHandler(t *testing.T) {
+	router := NewRouter()
+	router.Get("/", WithError(func(ctx *Context) error {
+		return NewProblem(http.StatusTeapot, "Teapot")
+	}))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	want := `{"error":{"message":"Teapot","code":418},"body":null}`
+	if w.Code != http.StatusTeapot || w.Body.String() != want {
+		t.Errorf("Got - %d %s, want - %d %s", w.Code, w.Body.String(), http.StatusTeapot, want)
+	}
+}
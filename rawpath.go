@@ -0,0 +1,117 @@
+package rou
+
+import (
+	"net/http"
+	"strings"
+)
+
+func unhex(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
+
+// Decodes percent-encoded octets of the escaped path except "%2F" and "%25".
+//
+// Decoded path has the same segments as the escaped one, so encoded slashes stay inside of segments,
+// and values of params are decoded only once by decodeKeptEscapes. Invalid escapes are kept as they are.
+func decodePathKeepingSlashes(escaped string) string {
+	if strings.IndexByte(escaped, '%') < 0 {
+		return escaped
+	}
+
+	var b strings.Builder
+	b.Grow(len(escaped))
+	for i := 0; i < len(escaped); i++ {
+		c := escaped[i]
+		if c == '%' && i+2 < len(escaped) && isHex(escaped[i+1]) && isHex(escaped[i+2]) {
+			if decoded := unhex(escaped[i+1])<<4 | unhex(escaped[i+2]); decoded != '/' && decoded != '%' {
+				b.WriteByte(decoded)
+				i += 2
+				continue
+			}
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// Decodes "%2F" and "%25" which are kept by decodePathKeepingSlashes
+func decodeKeptEscapes(value string) string {
+	if strings.IndexByte(value, '%') < 0 {
+		return value
+	}
+
+	var b strings.Builder
+	b.Grow(len(value))
+	for i := 0; i < len(value); i++ {
+		if value[i] == '%' && i+2 < len(value) {
+			switch value[i+1 : i+3] {
+			case "2F", "2f":
+				b.WriteByte('/')
+				i += 2
+				continue
+			case "25":
+				b.WriteByte('%')
+				i += 2
+				continue
+			}
+		}
+		b.WriteByte(value[i])
+	}
+	return b.String()
+}
+
+// Returns segment of the path by index or the rest of the path starting from the segment
+func pathSegment(path string, index int, rest bool) string {
+	path = routeKey(path)
+	for i := 0; i < index; i++ {
+		idx := strings.IndexByte(path, '/')
+		if idx < 0 {
+			return ""
+		}
+		path = path[idx+1:]
+	}
+	if rest {
+		return path
+	}
+	if idx := strings.IndexByte(path, '/'); idx >= 0 {
+		return path[:idx]
+	}
+	return path
+}
+
+// Returns path which is used to find route for the request.
+//
+// If UseRawPath is set escaped path is used with all octets decoded except encoded slashes and percent signs.
+func (sr *SimpleRouter) matchPath(r *http.Request) string {
+	if sr.UseRawPath {
+		return decodePathKeepingSlashes(r.URL.EscapedPath())
+	}
+	return r.URL.Path
+}
+
+// Stores values of route params to the context.
+//
+// If UseRawPath is set values are decoded and raw values are stored too.
+func (sr *SimpleRouter) setParams(ctx *Context, route *Route, values []string) {
+	if !sr.UseRawPath {
+		for i, name := range route.paramNames {
+			ctx.RouterParams().Set(name, values[i])
+		}
+		return
+	}
+
+	escapedPath := ctx.Request().URL.EscapedPath()
+	catchAll := isCatchAll(route.Path)
+	for i, name := range route.paramNames {
+		ctx.RouterParams().Set(name, decodeKeptEscapes(values[i]))
+		rest := catchAll && i == len(route.paramNames)-1
+		ctx.RawRouterParams().Set(name, pathSegment(escapedPath, route.paramSegments[i], rest))
+	}
+}
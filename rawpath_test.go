@@ -0,0 +1,119 @@
+package rou
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestUseRawPath(t *testing.T) {
+	tests := []struct {
+		name        string
+		route       string
+		requestPath string
+		param       string
+		want        string
+		wantRaw     string
+	}{
+		{name: "encoded slash", route: "/files/:name", requestPath: "/files/a%2Fb", param: "name", want: "a/b", wantRaw: "a%2Fb"},
+		{name: "encoded percent sign is decoded once", route: "/files/:name", requestPath: "/files/100%252F", param: "name", want: "100%2F", wantRaw: "100%252F"},
+		{name: "unicode", route: "/files/:name", requestPath: "/files/%D1%84%D0%B0%D0%B9%D0%BB", param: "name", want: "файл", wantRaw: "%D1%84%D0%B0%D0%B9%D0%BB"},
+		{name: "unicode in static segment", route: "/héllo/:name", requestPath: "/h%C3%A9llo/a%20b", param: "name", want: "a b", wantRaw: "a%20b"},
+		{name: "catch-all", route: "/files/*path", requestPath: "/files/dir/a%2Fb", param: "path", want: "dir/a/b", wantRaw: "dir/a%2Fb"},
+		{name: "param before catch-all", route: "/files/:dir/*path", requestPath: "/files/a%2Fb/c", param: "dir", want: "a/b", wantRaw: "a%2Fb"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := NewRouter()
+			router.UseRawPath = true
+			router.Get(test.route, func(ctx *Context) {
+				io.WriteString(ctx.ResponseWriter(), ctx.RouterParams().Get(test.param)+" "+ctx.RawRouterParams().Get(test.param))
+			})
+
+			newServer := httptest.NewServer(router)
+			defer newServer.Close()
+			res, err := http.Get(newServer.URL + test.requestPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			bytesRes, _ := io.ReadAll(res.Body)
+
+			want := test.want + " " + test.wantRaw
+			if string(bytesRes) != want {
+				t.Errorf("expected %q, got %q", want, string(bytesRes))
+			}
+		})
+	}
+
+	t.Run("encoded slash without raw path", func(t *testing.T) {
+		router := NewRouter()
+		router.Get("/files/:name", func(ctx *Context) {})
+
+		newServer := httptest.NewServer(router)
+		defer newServer.Close()
+		res, _ := http.Get(newServer.URL + "/files/a%2Fb")
+
+		if res.StatusCode != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, res.StatusCode)
+		}
+	})
+
+	t.Run("redirect keeps encoded slash", func(t *testing.T) {
+		router := NewRouter()
+		router.UseRawPath = true
+		router.PathPolicy = PathRedirect
+		router.Get("/files/:name", func(ctx *Context) {})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/files/a%2Fb/", nil))
+
+		want := "/files/a%2Fb"
+		if w.Header().Get("Location") != want {
+			t.Errorf("wrong location. Got - %q, want - %q", w.Header().Get("Location"), want)
+		}
+	})
+}
+
+func FuzzRawPathParams(f *testing.F) {
+	for _, seed := range []string{"a/b", "100%", "%2F", "%25", "файл", "a b?c#d", "%zz", "\xff"} {
+		f.Add(seed)
+	}
+
+	methodTree := newTree(MethodGet, defaultConstraints())
+	methodTree.insert(&Route{Path: "/files/:name/*rest"})
+
+	f.Fuzz(func(t *testing.T, value string) {
+		if value == "" {
+			return
+		}
+		escaped := url.PathEscape(value)
+		requestPath := decodePathKeepingSlashes("/files/" + escaped + "/" + escaped + "/" + escaped)
+
+		route, values := methodTree.lookup(requestPath)
+		if route == nil {
+			t.Fatalf("route is not found for %q", requestPath)
+		}
+		if got := decodeKeptEscapes(values[0]); got != value {
+			t.Errorf("wrong param. Got - %q, want - %q", got, value)
+		}
+		if got := decodeKeptEscapes(values[1]); got != value+"/"+value {
+			t.Errorf("wrong catch-all. Got - %q, want - %q", got, value+"/"+value)
+		}
+	})
+}
+
+func FuzzDecodePath(f *testing.F) {
+	for _, seed := range []string{"/a%2Fb", "/%252F", "/%zz", "/%", "/%2", "/%E2%82%AC", "/a%2fb%25"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, escaped string) {
+		decoded := decodeKeptEscapes(decodePathKeepingSlashes(escaped))
+		if want, err := url.PathUnescape(escaped); err == nil && decoded != want {
+			t.Errorf("path is not decoded once. Got - %q, want - %q", decoded, want)
+		}
+	})
+}
@@ -0,0 +1,315 @@
+package rou
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Encodes value in the format of content type
+type Renderer func(value any) ([]byte, error)
+
+// Returned by Renderer if the value can not be represented in its format, then the next acceptable format is tried
+var ErrNotRenderable = errors.New("rou: value can not be rendered in this format")
+
+const (
+	ContentTypeJSON = "application/json"
+	ContentTypeXML  = "application/xml"
+	ContentTypeText = "text/plain"
+	ContentTypeCSV  = "text/csv"
+)
+
+type renderer struct {
+	mediaType string
+	render    Renderer
+}
+
+// Returns built-in renderers in order of preference
+func defaultRenderers() []renderer {
+	return []renderer{
+		{ContentTypeJSON, json.Marshal},
+		{ContentTypeXML, renderXML},
+		{ContentTypeText, renderText},
+		{ContentTypeCSV, renderCSV},
+	}
+}
+
+// Adds renderer of the content type which is used by Context.Render, renderer of the same type is replaced
+//
+//	router.RegisterRenderer("application/yaml", func(value any) ([]byte, error) {
+//		return yaml.Marshal(value)
+//	})
+func (sr *SimpleRouter) RegisterRenderer(contentType string, render Renderer) {
+	if sr.renderers == nil {
+		sr.renderers = defaultRenderers()
+	}
+	mediaType := parseMediaType(contentType)
+	for i, existing := range sr.renderers {
+		if existing.mediaType == mediaType {
+			sr.renderers[i].render = render
+			return
+		}
+	}
+	sr.renderers = append(sr.renderers, renderer{mediaType, render})
+}
+
+// Returns lower-cased media type without params
+func parseMediaType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(contentType))
+	}
+	return mediaType
+}
+
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+// Parses Accept header, invalid ranges and ranges with invalid q-values are skipped
+func parseAccept(header string) []acceptRange {
+	var ranges []acceptRange
+	for _, item := range strings.Split(header, ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		mediaType, params, err := mime.ParseMediaType(item)
+		if err != nil || !strings.Contains(mediaType, "/") {
+			continue
+		}
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(value, 64); err != nil || q < 0 || q > 1 {
+				continue
+			}
+		}
+		ranges = append(ranges, acceptRange{mediaType, q})
+	}
+	return ranges
+}
+
+// Returns specificity of the range which matches media type: 3 for exact match, 2 for "type/*" and 1 for "*/*"
+func (a acceptRange) match(mediaType string) int {
+	switch {
+	case a.mediaType == mediaType:
+		return 3
+	case a.mediaType == "*/*":
+		return 1
+	case strings.HasSuffix(a.mediaType, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(a.mediaType, "*")):
+		return 2
+	}
+	return 0
+}
+
+// Returns acceptable renderers sorted by q-value of the most specific matching range, renderers are kept in order otherwise
+func negotiate(accept string, renderers []renderer) []renderer {
+	if strings.TrimSpace(accept) == "" {
+		return renderers
+	}
+
+	ranges := parseAccept(accept)
+	type candidate struct {
+		renderer
+		q float64
+	}
+	var candidates []candidate
+	for _, r := range renderers {
+		specificity, q := 0, 0.0
+		for _, item := range ranges {
+			if s := item.match(r.mediaType); s > specificity {
+				specificity, q = s, item.q
+			}
+		}
+		if specificity > 0 && q > 0 {
+			candidates = append(candidates, candidate{r, q})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+
+	result := make([]renderer, len(candidates))
+	for i, c := range candidates {
+		result[i] = c.renderer
+	}
+	return result
+}
+
+// Returns renderers of the router with renderer of ContentType first and content type which is written for it
+func (c Context) renderers() ([]renderer, string) {
+	renderers := defaultRenderers()
+	contentType := ""
+	if c.router != nil {
+		if c.router.renderers != nil {
+			renderers = c.router.renderers
+		}
+		contentType = c.router.ContentType
+	}
+	if contentType == "" {
+		return renderers, ""
+	}
+
+	mediaType := parseMediaType(contentType)
+	ordered := make([]renderer, 0, len(renderers))
+	for _, r := range renderers {
+		if r.mediaType == mediaType {
+			ordered = append([]renderer{r}, ordered...)
+		} else {
+			ordered = append(ordered, r)
+		}
+	}
+	return ordered, contentType
+}
+
+// Writes value with status in format chosen by Accept header of request: JSON, XML, plain text, CSV
+// or format of renderer registered by SimpleRouter.RegisterRenderer. Q-values of Accept header are honoured,
+// format of ContentType of the router is preferred if the client accepts several formats equally.
+//
+// Renders error with status 406 if none of formats is acceptable.
+func (c *Context) Render(status int, value any) {
+	renderers, defaultContentType := c.renderers()
+	for _, r := range negotiate(c.Request().Header.Get("Accept"), renderers) {
+		data, err := r.render(value)
+		if errors.Is(err, ErrNotRenderable) {
+			continue
+		}
+		if err != nil {
+			c.Error(NewHTTPError(http.StatusInternalServerError, MessageInternalServerError).Wrap(err))
+			return
+		}
+
+		contentType := r.mediaType
+		if defaultContentType != "" && parseMediaType(defaultContentType) == r.mediaType {
+			contentType = defaultContentType
+		} else if strings.HasPrefix(contentType, "text/") {
+			contentType += "; charset=utf-8"
+		}
+		header := c.ResponseWriter().Header()
+		header.Set("Content-Type", contentType)
+		header.Add("Vary", "Accept")
+		c.ResponseWriter().WriteHeader(status)
+		c.ResponseWriter().Write(data)
+		return
+	}
+	c.Error(NewHTTPError(http.StatusNotAcceptable, MessageNotAcceptable))
+}
+
+// Renders single element, slices and arrays are not renderable because they are encoded as several root elements
+func renderXML(value any) ([]byte, error) {
+	if _, ok := value.(xml.Marshaler); !ok {
+		t := reflect.TypeOf(value)
+		for t != nil && t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t.Elem().Kind() != reflect.Uint8 {
+			return nil, fmt.Errorf("%w: %s is not single XML element", ErrNotRenderable, t)
+		}
+	}
+	data, err := xml.Marshal(value)
+	var unsupported *xml.UnsupportedTypeError
+	if errors.As(err, &unsupported) {
+		return nil, fmt.Errorf("%w: %v", ErrNotRenderable, err)
+	}
+	return data, err
+}
+
+// Renders strings, numbers, booleans, errors and values which implement fmt.Stringer or encoding.TextMarshaler
+func renderText(value any) ([]byte, error) {
+	switch value := value.(type) {
+	case string:
+		return []byte(value), nil
+	case []byte:
+		return value, nil
+	case fmt.Stringer:
+		return []byte(value.String()), nil
+	case encoding.TextMarshaler:
+		return value.MarshalText()
+	case error:
+		return []byte(value.Error()), nil
+	}
+
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return []byte(fmt.Sprint(value)), nil
+	}
+	return nil, ErrNotRenderable
+}
+
+// Renders [][]string or slice of structs: names of columns are taken from "csv" tag, "json" tag or names of fields
+func renderCSV(value any) ([]byte, error) {
+	var records [][]string
+	if rows, ok := value.([][]string); ok {
+		records = rows
+	} else {
+		slice := reflect.ValueOf(value)
+		if slice.Kind() != reflect.Slice && slice.Kind() != reflect.Array {
+			return nil, ErrNotRenderable
+		}
+		elem := slice.Type().Elem()
+		for elem.Kind() == reflect.Pointer {
+			elem = elem.Elem()
+		}
+		if elem.Kind() != reflect.Struct {
+			return nil, ErrNotRenderable
+		}
+
+		var fields []int
+		var header []string
+		for i := 0; i < elem.NumField(); i++ {
+			field := elem.Field(i)
+			csvTag, hasCSV := field.Tag.Lookup("csv")
+			if !field.IsExported() || csvTag == "-" || !hasCSV && field.Tag.Get("json") == "-" {
+				continue
+			}
+			fields = append(fields, i)
+			header = append(header, fieldName(field, "csv"))
+		}
+
+		records = append(records, header)
+		for i := 0; i < slice.Len(); i++ {
+			row := indirect(slice.Index(i))
+			record := make([]string, len(fields))
+			for j, index := range fields {
+				if row.IsValid() {
+					record[j] = csvValue(row.Field(index))
+				}
+			}
+			records = append(records, record)
+		}
+	}
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	if err := writer.WriteAll(records); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// Returns text of the value for CSV cell, nil pointers are empty cells
+func csvValue(value reflect.Value) string {
+	value = indirect(value)
+	if !value.IsValid() {
+		return ""
+	}
+	switch v := value.Interface().(type) {
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case fmt.Stringer:
+		return v.String()
+	}
+	return fmt.Sprint(value.Interface())
+}
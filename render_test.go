@@ -0,0 +1,199 @@
+package rou
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+type renderUser struct {
+	ID     int     `json:"id" xml:"id"`
+	Name   string  `json:"name" xml:"name" csv:"full_name"`
+	Email  *string `json:"email,omitempty" xml:"email,omitempty"`
+	Secret string  `json:"-" xml:"-"`
+}
+
+func TestParseAccept(t *testing.T) {
+	got := parseAccept("text/html;level=1, application/json;q=0.5, */*;q=0.1, bad;q=1, text/csv;q=2")
+	want := []acceptRange{{"text/html", 1}, {"application/json", 0.5}, {"*/*", 0.1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got - %v, want - %v", got, want)
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   []string
+	}{
+		{accept: "", want: []string{ContentTypeJSON, ContentTypeXML, ContentTypeText, ContentTypeCSV}},
+		{accept: "*/*", want: []string{ContentTypeJSON, ContentTypeXML, ContentTypeText, ContentTypeCSV}},
+		{accept: "text/*", want: []string{ContentTypeText, ContentTypeCSV}},
+		{accept: "application/json;q=0.5, application/xml", want: []string{ContentTypeXML, ContentTypeJSON}},
+		{accept: "*/*;q=0.1, text/csv", want: []string{ContentTypeCSV, ContentTypeJSON, ContentTypeXML, ContentTypeText}},
+		{accept: "*/*, application/json;q=0", want: []string{ContentTypeXML, ContentTypeText, ContentTypeCSV}},
+		{accept: "image/png", want: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.accept, func(t *testing.T) {
+			var got []string
+			for _, r := range negotiate(test.accept, defaultRenderers()) {
+				got = append(got, r.mediaType)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Got - %v, want - %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	email := "john@example.com"
+	users := []renderUser{{ID: 1, Name: "John", Email: &email, Secret: "x"}, {ID: 2, Name: "Jane, Doe"}}
+
+	router := NewRouter()
+	router.Get("/users", func(ctx *Context) {
+		ctx.Render(http.StatusOK, users)
+	})
+	router.Get("/users/1", func(ctx *Context) {
+		ctx.Render(http.StatusCreated, users[0])
+	})
+	router.Get("/count", func(ctx *Context) {
+		ctx.Render(http.StatusOK, len(users))
+	})
+	router.Get("/map", func(ctx *Context) {
+		ctx.Render(http.StatusOK, map[string]int{"a": 1})
+	})
+
+	tests := []struct {
+		name        string
+		target      string
+		accept      string
+		status      int
+		contentType string
+		want        string
+	}{
+		{
+			name: "default", target: "/users/1",
+			status: http.StatusCreated, contentType: ContentTypeJSON,
+			want: `{"id":1,"name":"John","email":"john@example.com"}`,
+		},
+		{
+			name: "xml", target: "/users/1", accept: "application/xml",
+			status: http.StatusCreated, contentType: ContentTypeXML,
+			want: `<renderUser><id>1</id><name>John</name><email>john@example.com</email></renderUser>`,
+		},
+		{
+			name: "csv", target: "/users", accept: "text/csv, application/json;q=0.9",
+			status: http.StatusOK, contentType: "text/csv; charset=utf-8",
+			want: "id,full_name,email\n1,John,john@example.com\n2,\"Jane, Doe\",\n",
+		},
+		{
+			name: "text", target: "/count", accept: "text/plain",
+			status: http.StatusOK, contentType: "text/plain; charset=utf-8",
+			want: "2",
+		},
+		{
+			name: "next acceptable format", target: "/map", accept: "application/xml, application/json;q=0.5",
+			status: http.StatusOK, contentType: ContentTypeJSON,
+			want: `{"a":1}`,
+		},
+		{
+			name: "xml list is not renderable", target: "/users", accept: "application/xml, application/json;q=0.5",
+			status: http.StatusOK, contentType: ContentTypeJSON,
+			want: `[{"id":1,"name":"John","email":"john@example.com"},{"id":2,"name":"Jane, Doe"}]`,
+		},
+		{
+			name: "xml list is not acceptable", target: "/users", accept: "application/xml",
+			status: http.StatusNotAcceptable, contentType: ContentTypeJSON,
+			want: `{"error":{"message":"Not acceptable","code":406},"body":null}`,
+		},
+		{
+			name: "not renderable", target: "/map", accept: "text/*",
+			status: http.StatusNotAcceptable, contentType: ContentTypeJSON,
+			want: `{"error":{"message":"Not acceptable","code":406},"body":null}`,
+		},
+		{
+			name: "not acceptable", target: "/users/1", accept: "image/png",
+			status: http.StatusNotAcceptable, contentType: ContentTypeJSON,
+			want: `{"error":{"message":"Not acceptable","code":406},"body":null}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, test.target, nil)
+			if test.accept != "" {
+				r.Header.Set("Accept", test.accept)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+
+			if w.Code != test.status || w.Body.String() != test.want {
+				t.Errorf("Got - %d %q, want - %d %q", w.Code, w.Body.String(), test.status, test.want)
+			}
+			if contentType := w.Header().Get("Content-Type"); contentType != test.contentType {
+				t.Errorf("Got content type - %q, want - %q", contentType, test.contentType)
+			}
+		})
+	}
+}
+
+func TestRenderContentType(t *testing.T) {
+	router := NewRouter()
+	router.ContentType = "application/xml; charset=utf-8"
+	router.Get("/", func(ctx *Context) {
+		ctx.Render(http.StatusOK, renderUser{ID: 1})
+	})
+
+	for _, accept := range []string{"", "*/*", "application/json, application/xml"} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		if contentType := w.Header().Get("Content-Type"); contentType != router.ContentType {
+			t.Errorf("Accept %q: got content type - %q, want - %q", accept, contentType, router.ContentType)
+		}
+	}
+}
+
+func TestRegisterRenderer(t *testing.T) {
+	router := NewRouter()
+	router.RegisterRenderer("application/x-name", func(value any) ([]byte, error) {
+		user, ok := value.(renderUser)
+		if !ok {
+			return nil, ErrNotRenderable
+		}
+		return []byte(user.Name), nil
+	})
+	router.RegisterRenderer(ContentTypeText, func(value any) ([]byte, error) {
+		return nil, errors.New("broken")
+	})
+	router.Get("/user", func(ctx *Context) {
+		ctx.Render(http.StatusOK, renderUser{Name: "John"})
+	})
+
+	tests := []struct {
+		accept string
+		status int
+		want   string
+	}{
+		{accept: "application/x-name", status: http.StatusOK, want: "John"},
+		{accept: "text/plain", status: http.StatusInternalServerError, want: `{"error":{"message":"Internal server error","code":500},"body":null}`},
+	}
+
+	for _, test := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/user", nil)
+		r.Header.Set("Accept", test.accept)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		if w.Code != test.status || w.Body.String() != test.want {
+			t.Errorf("Accept %q: got - %d %s, want - %d %s", test.accept, w.Code, w.Body.String(), test.status, test.want)
+		}
+	}
+}
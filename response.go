@@ -0,0 +1,148 @@
+package rou
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Response writer of Context which records status, size of body and duration of the response.
+//
+// The first status is written, next calls of WriteHeader are ignored, so handlers and middlewares
+// can not cause "superfluous WriteHeader" warnings. It implements http.Flusher, http.Hijacker and io.ReaderFrom
+// which are passed to the wrapped writer if it supports them, Unwrap gives access to other features by http.ResponseController.
+type ResponseWriter interface {
+	http.ResponseWriter
+	http.Flusher
+	http.Hijacker
+	io.ReaderFrom
+	// Returns written status, it is 200 if only body is written and 0 if nothing is written
+	Status() int
+	// Returns number of written bytes of body
+	Size() int64
+	// Returns TRUE if status is written
+	Written() bool
+	// Returns time since the writer is created, e.g. since request is received by router
+	Duration() time.Duration
+	// Returns the wrapped writer
+	Unwrap() http.ResponseWriter
+}
+
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	size   int64
+	start  time.Time
+	// Discards body, it is used to answer HEAD requests by GET routes
+	discard bool
+}
+
+// Wraps writer to ResponseWriter
+func NewResponseWriter(w http.ResponseWriter) ResponseWriter {
+	return newResponseWriter(w)
+}
+
+// Returns writer as it is if it is created by router already, e.g. for mounted routers
+func newResponseWriter(w http.ResponseWriter) *responseWriter {
+	if wrapped, ok := w.(*responseWriter); ok {
+		return wrapped
+	}
+	return &responseWriter{ResponseWriter: w, start: time.Now()}
+}
+
+func (w *responseWriter) Status() int {
+	return w.status
+}
+
+func (w *responseWriter) Size() int64 {
+	return w.size
+}
+
+func (w *responseWriter) Written() bool {
+	return w.status != 0
+}
+
+func (w *responseWriter) Duration() time.Duration {
+	return time.Since(w.start)
+}
+
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Writes status once, informational statuses except 101 are passed without recording
+func (w *responseWriter) WriteHeader(status int) {
+	if status >= 100 && status < 200 && status != http.StatusSwitchingProtocols {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	if w.Written() {
+		return
+	}
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	if w.discard {
+		return len(b), nil
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *responseWriter) ReadFrom(r io.Reader) (int64, error) {
+	w.WriteHeader(http.StatusOK)
+	if w.discard {
+		return io.Copy(io.Discard, r)
+	}
+
+	var n int64
+	var err error
+	if readerFrom, ok := w.ResponseWriter.(io.ReaderFrom); ok {
+		n, err = readerFrom.ReadFrom(r)
+	} else {
+		n, err = io.Copy(writerOnly{w.ResponseWriter}, r)
+	}
+	w.size += n
+	return n, err
+}
+
+// Writes status 200 if nothing is written and flushes buffered data if the wrapped writer supports it
+func (w *responseWriter) Flush() {
+	w.WriteHeader(http.StatusOK)
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Returns http.ErrNotSupported if the wrapped writer can not be hijacked
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	return hijacker.Hijack()
+}
+
+// Hides io.ReaderFrom of the writer, so io.Copy does not call ReadFrom of responseWriter again
+type writerOnly struct {
+	io.Writer
+}
+
+// Returns ResponseWriter which is created for the request by router, it records status and size of the response
+// even if writer of the context is replaced by SetResponseWriter
+//
+//	router.Wrap(func(next rou.Handler) rou.Handler {
+//		return func(ctx *rou.Context) {
+//			next(ctx)
+//			log.Println(ctx.Request().URL, ctx.Response().Status(), ctx.Response().Size(), ctx.Response().Duration())
+//		}
+//	})
+func (c Context) Response() ResponseWriter {
+	return c.response
+}
@@ -0,0 +1,119 @@
+package rou
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestResponseWriter(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	w := NewResponseWriter(recorder)
+
+	if w.Written() || w.Status() != 0 || w.Size() != 0 {
+		t.Errorf("expected empty writer, got %d %d", w.Status(), w.Size())
+	}
+	w.WriteHeader(http.StatusCreated)
+	w.WriteHeader(http.StatusInternalServerError)
+	io.WriteString(w, "hello")
+	n, err := io.Copy(w, strings.NewReader(" world"))
+
+	if err != nil || n != 6 {
+		t.Errorf("ReadFrom: got %d, %v", n, err)
+	}
+	if !w.Written() || w.Status() != http.StatusCreated || w.Size() != 11 {
+		t.Errorf("Got - %d %d, want - %d %d", w.Status(), w.Size(), http.StatusCreated, 11)
+	}
+	if recorder.Code != http.StatusCreated || recorder.Body.String() != "hello world" {
+		t.Errorf("Got response - %d %q", recorder.Code, recorder.Body.String())
+	}
+	if w.Unwrap() != recorder || NewResponseWriter(w) != w {
+		t.Error("expected wrapped writer to be kept")
+	}
+
+	w.Flush()
+	if !recorder.Flushed {
+		t.Error("expected Flush to be passed to wrapped writer")
+	}
+	if _, _, err := w.Hijack(); !errors.Is(err, http.ErrNotSupported) {
+		t.Errorf("Hijack: got %v", err)
+	}
+}
+
+func TestResponseWriterImplicitStatus(t *testing.T) {
+	for name, write := range map[string]func(w ResponseWriter){
+		"write": func(w ResponseWriter) { w.Write([]byte("body")) },
+		"flush": func(w ResponseWriter) { w.Flush() },
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := NewResponseWriter(httptest.NewRecorder())
+			write(w)
+			w.WriteHeader(http.StatusNotFound)
+			if w.Status() != http.StatusOK {
+				t.Errorf("Got - %d, want - %d", w.Status(), http.StatusOK)
+			}
+		})
+	}
+}
+
+func TestContextResponse(t *testing.T) {
+	var status int
+	var size int64
+	var sameWriter bool
+
+	router := NewRouter()
+	router.Wrap(func(next Handler) Handler {
+		return func(ctx *Context) {
+			ctx.SetResponseWriter(struct{ http.ResponseWriter }{ctx.ResponseWriter()})
+			next(ctx)
+			status, size = ctx.Response().Status(), ctx.Response().Size()
+		}
+	})
+	router.Get("/users", func(ctx *Context) {
+		ctx.ResponseWriter().WriteHeader(http.StatusAccepted)
+		ctx.SuccessJSONResponse("ok")
+	})
+	api := NewRouter()
+	api.Get("/status", func(ctx *Context) {
+		ctx.ResponseWriter().WriteHeader(http.StatusTeapot)
+	})
+	router.Mount("/api", api)
+	router.Get("/mounted", func(ctx *Context) {
+		inner := NewRouter()
+		inner.Get("/mounted", func(innerCtx *Context) {
+			sameWriter = innerCtx.Response() == ctx.Response()
+		})
+		inner.ServeHTTP(ctx.Response(), ctx.Request())
+	})
+
+	tests := []struct {
+		method string
+		target string
+		status int
+		size   int64
+		body   string
+	}{
+		{method: http.MethodGet, target: "/users", status: http.StatusAccepted, size: 26, body: `{"error":null,"body":"ok"}`},
+		{method: http.MethodHead, target: "/users", status: http.StatusAccepted, size: 0, body: ``},
+		{method: http.MethodGet, target: "/api/status", status: http.StatusTeapot, size: 0, body: ``},
+	}
+
+	for _, test := range tests {
+		t.Run(test.method+" "+test.target, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(test.method, test.target, nil))
+
+			if status != test.status || size != test.size || w.Code != test.status || w.Body.String() != test.body {
+				t.Errorf("Got - %d %d %d %q, want - %d %d %q", status, size, w.Code, w.Body.String(), test.status, test.size, test.body)
+			}
+		})
+	}
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/mounted", nil))
+	if !sameWriter {
+		t.Error("expected router to reuse response writer of the context")
+	}
+}
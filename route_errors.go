@@ -0,0 +1,115 @@
+package rou
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Error of route registration when the route can not be stored or may be matched in unexpected way
+type RouteConflictError struct {
+	Method string
+	Path   string
+	// File and line where the route has been registered
+	Location string
+
+	// Route which has been registered before, it is empty for invalid routes
+	ExistingPath     string
+	ExistingLocation string
+
+	// Describes the reason of conflict
+	Reason string
+	// Conflicts when both routes can be stored and matched, e.g. params with different names at the same position.
+	// Such conflicts are reported only in strict mode.
+	Ambiguous bool
+}
+
+func (e *RouteConflictError) Error() string {
+	if e.ExistingPath == "" {
+		return fmt.Sprintf("rou: route %s %s (%s): %s", e.Method, e.Path, e.Location, e.Reason)
+	}
+	return fmt.Sprintf("rou: route %s %s (%s) conflicts with %s %s (%s): %s",
+		e.Method, e.Path, e.Location,
+		e.Method, e.ExistingPath, e.ExistingLocation,
+		e.Reason,
+	)
+}
+
+// List of errors found in registered routes
+type RouteErrors []error
+
+func (e RouteErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Route which can not be stored and its conflict
+type routeRejection struct {
+	routes *routes
+	err    *RouteConflictError
+}
+
+// Reports call of the method on route which is not stored, because the call has no effect
+func (r *Route) reportRejected(method string) {
+	if r.rejection == nil {
+		return
+	}
+	r.rejection.routes.errors = append(r.rejection.routes.errors, &RouteConflictError{
+		Method:           r.method,
+		Path:             r.Path,
+		Location:         r.location,
+		ExistingPath:     r.rejection.err.ExistingPath,
+		ExistingLocation: r.rejection.err.ExistingLocation,
+		Reason:           fmt.Sprintf("%s called at %s has no effect because the route is not registered: %s", method, callerLocation(), r.rejection.err.Reason),
+	})
+}
+
+var packageDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// Returns file and line of the first caller outside of this package
+func callerLocation() string {
+	pc := make([]uintptr, 16)
+	n := runtime.Callers(2, pc)
+	frames := runtime.CallersFrames(pc[:n])
+	for {
+		frame, more := frames.Next()
+		if filepath.Dir(frame.File) != packageDir || strings.HasSuffix(frame.File, "_test.go") {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// Checks all registered routes.
+//
+// Returns RouteErrors with duplicated and invalid routes. In strict mode ambiguous routes are reported too,
+// e.g. "/a/:x/b" and "/a/:y/c" which use different names for the param at the same position.
+func (sr SimpleRouter) Validate() error {
+	var errs RouteErrors
+	for _, err := range sr.Routes.errors {
+		if err.Ambiguous && !sr.Strict {
+			continue
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Panics if Validate returns an error. It should be called after all routes have been registered.
+func (sr SimpleRouter) MustCompile() {
+	if err := sr.Validate(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,140 @@
+package rou
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRouteConflicts(t *testing.T) {
+	fakeHandler := func(ctx *Context) {}
+
+	t.Run("calls on duplicated route are reported", func(t *testing.T) {
+		router := NewRouter()
+		router.Get("/users", fakeHandler)
+		router.Get("/users", fakeHandler).Name("users").Describe(RouteDoc{}).Middleware(middlewares...)
+
+		if len(router.GetRoutes(MethodGet)) != 1 {
+			t.Errorf("expected only one stored route, got %d", len(router.GetRoutes(MethodGet)))
+		}
+		var errs RouteErrors
+		if !errors.As(router.Validate(), &errs) || len(errs) != 4 {
+			t.Fatalf("expected errors for duplicated route and calls of Name, Describe and Middleware, got %v", router.Validate())
+		}
+		for i, method := range []string{"Name", "Describe", "Middleware"} {
+			var conflict *RouteConflictError
+			if !errors.As(errs[i+1], &conflict) {
+				t.Fatalf("expected RouteConflictError, got %v", errs[i+1])
+			}
+			if !strings.HasPrefix(conflict.Reason, method+" called at ") || !strings.Contains(conflict.Reason, "route_errors_test.go") ||
+				!strings.Contains(conflict.Location, "route_errors_test.go") || !strings.Contains(conflict.ExistingLocation, "route_errors_test.go") {
+				t.Errorf("expected call site and both registration sites, got %v", conflict)
+			}
+		}
+		if _, err := router.URL("users"); err == nil {
+			t.Error("expected name of duplicated route not to be registered")
+		}
+	})
+
+	t.Run("first of duplicated routes is served", func(t *testing.T) {
+		router := NewRouter()
+		router.Get("/users", func(ctx *Context) { ctx.ResponseWriter().WriteHeader(http.StatusAccepted) })
+		router.Get("/users/", fakeHandler)
+
+		newServer := httptest.NewServer(router)
+		defer newServer.Close()
+		res, _ := http.Get(newServer.URL + "/users")
+		if res.StatusCode != http.StatusAccepted {
+			t.Errorf("expected status %d, got %d", http.StatusAccepted, res.StatusCode)
+		}
+	})
+
+	t.Run("error describes both routes", func(t *testing.T) {
+		router := NewRouter()
+		router.Get("/a/:x", fakeHandler)
+		router.Get("/a/:y", fakeHandler)
+
+		err := router.Validate()
+		var routeErrors RouteErrors
+		if !errors.As(err, &routeErrors) || len(routeErrors) != 1 {
+			t.Fatalf("expected one route error, got %v", err)
+		}
+		var conflict *RouteConflictError
+		if !errors.As(routeErrors[0], &conflict) {
+			t.Fatalf("expected RouteConflictError, got %T", routeErrors[0])
+		}
+		if conflict.Path != "/a/:y" || conflict.ExistingPath != "/a/:x" || conflict.Method != MethodGet {
+			t.Errorf("wrong routes in conflict: %+v", conflict)
+		}
+		if !strings.Contains(conflict.Location, "route_errors_test.go") || !strings.Contains(conflict.ExistingLocation, "route_errors_test.go") {
+			t.Errorf("locations should point to registration. Got - %q and %q", conflict.Location, conflict.ExistingLocation)
+		}
+	})
+
+	t.Run("same path with different methods", func(t *testing.T) {
+		router := NewRouter()
+		router.Get("/users", fakeHandler)
+		router.Post("/users", fakeHandler)
+
+		if err := router.Validate(); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("ambiguous param names are reported only in strict mode", func(t *testing.T) {
+		router := NewRouter()
+		router.Get("/users/:id/posts", fakeHandler)
+		router.Get("/users/:name/profile", fakeHandler)
+
+		if err := router.Validate(); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		router.Strict = true
+		err := router.Validate()
+		if err == nil || !strings.Contains(err.Error(), `param "name" is registered as "id"`) {
+			t.Errorf("expected ambiguous param error, got %v", err)
+		}
+	})
+
+	t.Run("invalid routes", func(t *testing.T) {
+		invalidRoutes := []string{"/files/*path/edit", "/users/:", "/*"}
+		for _, route := range invalidRoutes {
+			router := NewRouter()
+			router.Get(route, fakeHandler)
+
+			if router.Validate() == nil {
+				t.Errorf("expected error for route %q", route)
+			}
+			if len(router.GetRoutes(MethodGet)) != 0 {
+				t.Errorf("invalid route %q should not be stored", route)
+			}
+		}
+	})
+
+	t.Run("MustCompile panics with errors", func(t *testing.T) {
+		router := NewRouter()
+		router.Get("/users", fakeHandler)
+		router.Get("/users", fakeHandler)
+
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic")
+			}
+		}()
+		router.MustCompile()
+	})
+
+	t.Run("strict router does not run server with errors", func(t *testing.T) {
+		router := NewRouter()
+		router.Strict = true
+		router.Get("/users", fakeHandler)
+		router.Get("/users", fakeHandler)
+
+		if err := router.RunServer("127.0.0.1:0"); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
@@ -0,0 +1,516 @@
+package rou
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// List of JSON Schema types, it is rendered as a string if there is only one type
+type SchemaType []string
+
+func (t SchemaType) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+func (t *SchemaType) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = SchemaType{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*t = list
+	return nil
+}
+
+// Returns TRUE if the list has the type
+func (t SchemaType) Has(name string) bool {
+	for _, item := range t {
+		if item == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Value of "additionalProperties" which is either a boolean or a schema
+type AdditionalProperties struct {
+	Allowed bool
+	Schema  *Schema
+}
+
+func (a AdditionalProperties) MarshalJSON() ([]byte, error) {
+	if a.Schema != nil {
+		return json.Marshal(a.Schema)
+	}
+	return json.Marshal(a.Allowed)
+}
+
+func (a *AdditionalProperties) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &a.Allowed); err == nil {
+		return nil
+	}
+	a.Allowed = true
+	return json.Unmarshal(data, &a.Schema)
+}
+
+// Subset of JSON Schema which is used in OpenAPI documents
+type Schema struct {
+	Ref         string     `json:"$ref,omitempty"`
+	Type        SchemaType `json:"type,omitempty"`
+	Format      string     `json:"format,omitempty"`
+	Description string     `json:"description,omitempty"`
+	// Allows null in OpenAPI 3.0 documents, it is ignored in 3.1 documents and is not written by generator which uses "null" type
+	Nullable             bool                  `json:"nullable,omitempty"`
+	Enum                 []any                 `json:"enum,omitempty"`
+	Pattern              string                `json:"pattern,omitempty"`
+	Minimum              *float64              `json:"minimum,omitempty"`
+	Maximum              *float64              `json:"maximum,omitempty"`
+	MinLength            *int                  `json:"minLength,omitempty"`
+	MaxLength            *int                  `json:"maxLength,omitempty"`
+	MinItems             *int                  `json:"minItems,omitempty"`
+	MaxItems             *int                  `json:"maxItems,omitempty"`
+	Items                *Schema               `json:"items,omitempty"`
+	Properties           map[string]*Schema    `json:"properties,omitempty"`
+	Required             []string              `json:"required,omitempty"`
+	AdditionalProperties *AdditionalProperties `json:"additionalProperties,omitempty"`
+	AllOf                []*Schema             `json:"allOf,omitempty"`
+	AnyOf                []*Schema             `json:"anyOf,omitempty"`
+	OneOf                []*Schema             `json:"oneOf,omitempty"`
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Reflects Go types into JSON Schema, named struct types are stored in schemas and referenced by $ref
+type schemaGenerator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+	// Envelope of described responses
+	envelope Envelope
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{schemas: make(map[string]*Schema), names: make(map[reflect.Type]string)}
+}
+
+// Returns name of the type which can be used as a key of components
+func (g *schemaGenerator) typeName(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+
+	var b strings.Builder
+	for _, c := range t.Name() {
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' {
+			b.WriteRune(c)
+		} else if c == '[' || c == ',' {
+			b.WriteByte('_')
+		}
+	}
+	base := b.String()
+	name := base
+	for i := 2; g.schemas[name] != nil; i++ {
+		name = base + "_" + strconv.Itoa(i)
+	}
+	g.names[t] = name
+	return name
+}
+
+func float(value float64) *float64 {
+	return &value
+}
+
+// Returns schema of the Go type
+func (g *schemaGenerator) schema(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+
+	if t.Kind() == reflect.Pointer {
+		schema := g.schema(t.Elem())
+		if schema.Ref != "" {
+			return &Schema{AnyOf: []*Schema{schema, {Type: SchemaType{"null"}}}}
+		}
+		if len(schema.Type) > 0 {
+			schema.Type = append(schema.Type, "null")
+		}
+		return schema
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: SchemaType{"string"}, Format: "date-time"}
+	case t == rawMessageType:
+		return &Schema{}
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return &Schema{Type: SchemaType{"string"}}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: SchemaType{"boolean"}}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: SchemaType{"integer"}, Format: "int32"}
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: SchemaType{"integer"}, Format: "int64"}
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: SchemaType{"integer"}, Format: "int32", Minimum: float(0)}
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: SchemaType{"integer"}, Format: "int64", Minimum: float(0)}
+	case reflect.Float32:
+		return &Schema{Type: SchemaType{"number"}, Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: SchemaType{"number"}, Format: "double"}
+	case reflect.String:
+		return &Schema{Type: SchemaType{"string"}}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: SchemaType{"string"}, Format: "byte"}
+		}
+		return &Schema{Type: SchemaType{"array"}, Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: SchemaType{"object"}, AdditionalProperties: &AdditionalProperties{Allowed: true, Schema: g.schema(t.Elem())}}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := g.typeName(t)
+		if _, ok := g.schemas[name]; !ok {
+			g.schemas[name] = &Schema{}
+			*g.schemas[name] = *g.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	default:
+		return &Schema{}
+	}
+}
+
+// Parses json tag of the field, returns empty name if the field is skipped
+func jsonFieldName(field reflect.StructField) (name string, omitempty bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: SchemaType{"object"}, Properties: make(map[string]*Schema)}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				inline := g.structSchema(embedded)
+				for name, property := range inline.Properties {
+					schema.Properties[name] = property
+				}
+				schema.Required = append(schema.Required, inline.Required...)
+				continue
+			}
+		}
+
+		name, omitempty := jsonFieldName(field)
+		if name == "" || isRequestField(field) {
+			continue
+		}
+		property := g.schema(field.Type)
+		if description := field.Tag.Get("doc"); description != "" {
+			if property.Ref != "" {
+				property = &Schema{AllOf: []*Schema{property}}
+			}
+			property.Description = description
+		}
+		schema.Properties[name] = property
+		if !omitempty {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return schema
+}
+
+// Violation of the schema by the value of request
+type SchemaViolation struct {
+	// Part of request: "path", "query", "header" or "body"
+	In string `json:"in"`
+	// Name of param or JSON pointer to the invalid value of body
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+var patterns sync.Map
+
+// Returns compiled pattern, compiled patterns are cached
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if compiled, ok := patterns.Load(pattern); ok {
+		return compiled.(*regexp.Regexp), nil
+	}
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(pattern, compiled)
+	return compiled, nil
+}
+
+// Validates decoded JSON values by schemas, references are resolved by schemas of components
+type schemaValidator struct {
+	schemas map[string]*Schema
+	in      string
+	// Nullable keyword of OpenAPI 3.0 allows null
+	nullable   bool
+	violations []SchemaViolation
+}
+
+func (v *schemaValidator) report(path string, format string, args ...any) {
+	v.violations = append(v.violations, SchemaViolation{In: v.in, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// Returns schema by reference to components
+func (v *schemaValidator) resolve(schema *Schema) (*Schema, error) {
+	for depth := 0; schema.Ref != ""; depth++ {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		resolved, ok := v.schemas[name]
+		if !ok || name == schema.Ref || depth > 32 {
+			return nil, fmt.Errorf("rou: unknown schema reference %q", schema.Ref)
+		}
+		schema = resolved
+	}
+	return schema, nil
+}
+
+// Returns JSON Schema type of the decoded JSON value
+func jsonType(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if _, err := value.Int64(); err == nil {
+			return "integer"
+		}
+		if number, err := value.Float64(); err == nil && number == math.Trunc(number) {
+			return "integer"
+		}
+		return "number"
+	case float64:
+		if value == math.Trunc(value) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return ""
+}
+
+func numberValue(value any) float64 {
+	switch value := value.(type) {
+	case json.Number:
+		number, _ := value.Float64()
+		return number
+	case float64:
+		return value
+	}
+	return 0
+}
+
+func (v *schemaValidator) hasType(schema *Schema, valueType string) bool {
+	if len(schema.Type) == 0 || schema.Type.Has(valueType) {
+		return true
+	}
+	if valueType == "integer" && schema.Type.Has("number") {
+		return true
+	}
+	return valueType == "null" && v.nullable && schema.Nullable
+}
+
+// Checks that the string has the format, unknown formats are not checked
+func hasFormat(value string, format string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	case "date":
+		return isDate(value)
+	case "uuid":
+		return isUUID(value)
+	case "email":
+		at := strings.IndexByte(value, '@')
+		return at > 0 && at < len(value)-1
+	}
+	return true
+}
+
+// Validates value by schema and reports violations with the path as JSON pointer
+func (v *schemaValidator) validate(schema *Schema, value any, path string) {
+	schema, err := v.resolve(schema)
+	if err != nil {
+		v.report(path, "%s", err.Error())
+		return
+	}
+
+	valueType := jsonType(value)
+	if !v.hasType(schema, valueType) {
+		v.report(path, "expected %s, got %s", strings.Join(schema.Type, " or "), valueType)
+		return
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		v.report(path, "value is not one of allowed values")
+	}
+
+	switch value := value.(type) {
+	case string:
+		length := utf8.RuneCountInString(value)
+		if schema.MinLength != nil && length < *schema.MinLength {
+			v.report(path, "length should be at least %d", *schema.MinLength)
+		}
+		if schema.MaxLength != nil && length > *schema.MaxLength {
+			v.report(path, "length should be at most %d", *schema.MaxLength)
+		}
+		if schema.Pattern != "" {
+			if pattern, err := compilePattern(schema.Pattern); err == nil && !pattern.MatchString(value) {
+				v.report(path, "value does not match pattern %q", schema.Pattern)
+			}
+		}
+		if !hasFormat(value, schema.Format) {
+			v.report(path, "value is not valid %s", schema.Format)
+		}
+	case json.Number, float64:
+		number := numberValue(value)
+		if schema.Minimum != nil && number < *schema.Minimum {
+			v.report(path, "value should be at least %v", *schema.Minimum)
+		}
+		if schema.Maximum != nil && number > *schema.Maximum {
+			v.report(path, "value should be at most %v", *schema.Maximum)
+		}
+	case []any:
+		if schema.MinItems != nil && len(value) < *schema.MinItems {
+			v.report(path, "number of items should be at least %d", *schema.MinItems)
+		}
+		if schema.MaxItems != nil && len(value) > *schema.MaxItems {
+			v.report(path, "number of items should be at most %d", *schema.MaxItems)
+		}
+		if schema.Items != nil {
+			for i, item := range value {
+				v.validate(schema.Items, item, path+"/"+strconv.Itoa(i))
+			}
+		}
+	case map[string]any:
+		v.validateObject(schema, value, path)
+	}
+
+	for _, subSchema := range schema.AllOf {
+		v.validate(subSchema, value, path)
+	}
+	if len(schema.AnyOf) > 0 && v.matches(schema.AnyOf, value, path) == 0 {
+		v.report(path, "value does not match any of schemas")
+	}
+	if len(schema.OneOf) > 0 && v.matches(schema.OneOf, value, path) != 1 {
+		v.report(path, "value should match exactly one of schemas")
+	}
+}
+
+func (v *schemaValidator) validateObject(schema *Schema, value map[string]any, path string) {
+	for _, name := range schema.Required {
+		if _, ok := value[name]; !ok {
+			v.report(path+"/"+escapePointer(name), "value is required")
+		}
+	}
+
+	names := make([]string, 0, len(value))
+	for name := range value {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		propertyPath := path + "/" + escapePointer(name)
+		if property, ok := schema.Properties[name]; ok {
+			v.validate(property, value[name], propertyPath)
+			continue
+		}
+		if additional := schema.AdditionalProperties; additional != nil {
+			if additional.Schema != nil {
+				v.validate(additional.Schema, value[name], propertyPath)
+			} else if !additional.Allowed {
+				v.report(propertyPath, "property is not allowed")
+			}
+		}
+	}
+}
+
+// Returns number of schemas which the value matches
+func (v *schemaValidator) matches(schemas []*Schema, value any, path string) int {
+	matched := 0
+	for _, schema := range schemas {
+		sub := schemaValidator{schemas: v.schemas, in: v.in, nullable: v.nullable}
+		sub.validate(schema, value, path)
+		if len(sub.violations) == 0 {
+			matched++
+		}
+	}
+	return matched
+}
+
+func inEnum(enum []any, value any) bool {
+	for _, item := range enum {
+		if jsonType(item) == "integer" || jsonType(item) == "number" {
+			if (jsonType(value) == "integer" || jsonType(value) == "number") && numberValue(item) == numberValue(value) {
+				return true
+			}
+			continue
+		}
+		if reflect.DeepEqual(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Escapes name of property for JSON pointer
+func escapePointer(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
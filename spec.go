@@ -0,0 +1,403 @@
+package rou
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Reads OpenAPI 3 document in JSON format
+func LoadOpenAPI(r io.Reader) (*OpenAPIDocument, error) {
+	var document OpenAPIDocument
+	if err := json.NewDecoder(r).Decode(&document); err != nil {
+		return nil, fmt.Errorf("rou: invalid OpenAPI document: %w", err)
+	}
+	if !strings.HasPrefix(document.OpenAPI, "3.") {
+		return nil, fmt.Errorf("rou: unsupported OpenAPI version %q", document.OpenAPI)
+	}
+	return &document, nil
+}
+
+// Reads OpenAPI 3 document from the JSON file
+func LoadOpenAPIFile(name string) (*OpenAPIDocument, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return LoadOpenAPI(file)
+}
+
+// Converts OpenAPI path template to the route: "/users/{id}" to "/users/:id"
+func templateRoute(template string) (string, error) {
+	segments := strings.Split(template, "/")
+	for i, segment := range segments {
+		if !strings.ContainsAny(segment, "{}") {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(segment, "{"), "}")
+		if name == "" || len(name) != len(segment)-2 || strings.ContainsAny(name, "{}") {
+			return "", fmt.Errorf("rou: path %q: only whole segments can be templated", template)
+		}
+		segments[i] = ":" + name
+	}
+	return strings.Join(segments, "/"), nil
+}
+
+// Validates requests of the operation before its handler runs
+type operationValidator struct {
+	parameters  []*OpenAPIParameter
+	requestBody *OpenAPIRequestBody
+	schemas     map[string]*Schema
+	// Document is OpenAPI 3.0, nullable keyword of its schemas allows null
+	nullable bool
+}
+
+// Returns params of the path and the operation with resolved references,
+// params of the operation override params of the path with the same name and location
+func resolveParameters(document *OpenAPIDocument, item *OpenAPIPathItem, operation *OpenAPIOperation) ([]*OpenAPIParameter, error) {
+	var parameters []*OpenAPIParameter
+	for _, parameter := range append(append([]*OpenAPIParameter(nil), item.Parameters...), operation.Parameters...) {
+		if parameter.Ref != "" {
+			name := strings.TrimPrefix(parameter.Ref, "#/components/parameters/")
+			var resolved *OpenAPIParameter
+			if document.Components != nil {
+				resolved = document.Components.Parameters[name]
+			}
+			if resolved == nil {
+				return nil, fmt.Errorf("rou: unknown parameter reference %q", parameter.Ref)
+			}
+			parameter = resolved
+		}
+
+		for i, existing := range parameters {
+			if existing.Name == parameter.Name && existing.In == parameter.In {
+				parameters = append(parameters[:i], parameters[i+1:]...)
+				break
+			}
+		}
+		parameters = append(parameters, parameter)
+	}
+	return parameters, nil
+}
+
+// Converts values of param to JSON value by schema, values which can not be converted are kept as strings
+func (v *schemaValidator) paramValue(schema *Schema, values []string) any {
+	resolved, err := v.resolve(schema)
+	if err != nil {
+		return values[0]
+	}
+	if resolved.Type.Has("array") {
+		items := make([]any, 0, len(values))
+		for _, value := range values {
+			item := any(value)
+			if resolved.Items != nil {
+				item = v.paramValue(resolved.Items, []string{value})
+			}
+			items = append(items, item)
+		}
+		return items
+	}
+
+	value := values[0]
+	switch {
+	case resolved.Type.Has("integer"):
+		if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return json.Number(value)
+		}
+	case resolved.Type.Has("number"):
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			return json.Number(value)
+		}
+	case resolved.Type.Has("boolean"):
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+	return value
+}
+
+// Returns values of the param from the request
+func requestParamValues(ctx *Context, parameter *OpenAPIParameter) []string {
+	request := ctx.Request()
+	switch parameter.In {
+	case "path":
+		if ctx.RouterParams().Has(parameter.Name) {
+			return []string{ctx.RouterParams().Get(parameter.Name)}
+		}
+	case "query":
+		return request.URL.Query()[parameter.Name]
+	case "header":
+		return request.Header.Values(parameter.Name)
+	case "cookie":
+		if cookie, err := request.Cookie(parameter.Name); err == nil {
+			return []string{cookie.Value}
+		}
+	}
+	return nil
+}
+
+// Returns media type of the request body which is described by the operation
+func (o *operationValidator) mediaType(contentType string) (string, *OpenAPIMediaType, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = contentType
+	}
+	if media, ok := o.requestBody.Content[mediaType]; ok {
+		return mediaType, media, true
+	}
+	if slash := strings.IndexByte(mediaType, '/'); slash > 0 {
+		if media, ok := o.requestBody.Content[mediaType[:slash]+"/*"]; ok {
+			return mediaType, media, true
+		}
+	}
+	media, ok := o.requestBody.Content["*/*"]
+	return mediaType, media, ok
+}
+
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// Validates body of the request and restores it for the handler, size of body is limited by MaxBodySize of the router
+func (o *operationValidator) checkBody(ctx *Context, validator *schemaValidator) error {
+	request := ctx.Request()
+	var data []byte
+	if request.Body != nil {
+		body := request.Body
+		if limit := ctx.maxBodySize(); limit > 0 {
+			body = http.MaxBytesReader(ctx.ResponseWriter(), body, limit)
+		}
+		var err error
+		data, err = io.ReadAll(body)
+		body.Close()
+		if err != nil {
+			return bodyError(err)
+		}
+		request.Body = io.NopCloser(bytes.NewReader(data))
+	}
+
+	if len(data) == 0 {
+		if o.requestBody.Required {
+			validator.report("", "body is required")
+		}
+		return nil
+	}
+
+	mediaType, media, ok := o.mediaType(request.Header.Get("Content-Type"))
+	if !ok {
+		return NewHTTPError(http.StatusUnsupportedMediaType, MessageUnsupportedMediaType)
+	}
+	if !isJSONMediaType(mediaType) || media == nil || media.Schema == nil {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		validator.report("", "body is not valid JSON")
+		return nil
+	}
+	validator.validate(media.Schema, value, "")
+	return nil
+}
+
+// Validates params and body of the request, returns HTTPError with violations in details
+func (o *operationValidator) check(ctx *Context) error {
+	validator := &schemaValidator{schemas: o.schemas, nullable: o.nullable}
+	for _, parameter := range o.parameters {
+		validator.in = parameter.In
+		values := requestParamValues(ctx, parameter)
+		if len(values) == 0 {
+			if parameter.Required || parameter.In == "path" {
+				validator.report(parameter.Name, "value is required")
+			}
+			continue
+		}
+		if parameter.Schema != nil {
+			validator.validate(parameter.Schema, validator.paramValue(parameter.Schema, values), parameter.Name)
+		}
+	}
+
+	paramViolations := len(validator.violations)
+	if o.requestBody != nil {
+		validator.in = "body"
+		if err := o.checkBody(ctx, validator); err != nil {
+			return err
+		}
+	}
+
+	if len(validator.violations) == 0 {
+		return nil
+	}
+	message := MessageRequestIsNotValid
+	if paramViolations == 0 {
+		message = MessageBodyIsNotValid
+	}
+	return NewHTTPError(http.StatusBadRequest, message).WithDetails(validator.violations)
+}
+
+func (o *operationValidator) middleware(next Handler) Handler {
+	return func(ctx *Context) {
+		if err := o.check(ctx); err != nil {
+			ctx.Error(err)
+			return
+		}
+		next(ctx)
+	}
+}
+
+// Checks that all schema references of the document can be resolved
+func checkReferences(document *OpenAPIDocument, schema *Schema, checked map[*Schema]bool) error {
+	if schema == nil || checked[schema] {
+		return nil
+	}
+	checked[schema] = true
+
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		if document.Components == nil || document.Components.Schemas[name] == nil {
+			return fmt.Errorf("rou: unknown schema reference %q", schema.Ref)
+		}
+		return checkReferences(document, document.Components.Schemas[name], checked)
+	}
+
+	children := append([]*Schema{schema.Items}, schema.AllOf...)
+	children = append(append(children, schema.AnyOf...), schema.OneOf...)
+	for _, property := range schema.Properties {
+		children = append(children, property)
+	}
+	if schema.AdditionalProperties != nil {
+		children = append(children, schema.AdditionalProperties.Schema)
+	}
+	for _, child := range children {
+		if err := checkReferences(document, child, checked); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type specRoute struct {
+	method    string
+	path      string
+	operation *OpenAPIOperation
+	handler   func(*Context)
+	validator *operationValidator
+}
+
+// Registers routes of the document with handlers by operationId of operations.
+//
+// Path params, query params, headers, cookies and JSON bodies of requests are validated by schemas of the document
+// before the handler runs, violations are rendered by ErrorHandler as HTTPError with status 400
+// and list of SchemaViolation in details. Bodies with media types which are not described are rejected with status 415,
+// bodies larger than MaxBodySize of the router with status 413.
+// Schemas of OpenAPI 3.0 documents allow null by "nullable" keyword, schemas of 3.1 documents by "null" type.
+//
+// Returns RouteErrors and registers nothing if some operations do not have operationId or handler,
+// some handlers do not match operations or the document has unknown references.
+func RegisterOpenAPI(r Registrar, document *OpenAPIDocument, handlers map[string]func(*Context)) error {
+	var schemas map[string]*Schema
+	if document.Components != nil {
+		schemas = document.Components.Schemas
+	}
+	nullable := strings.HasPrefix(document.OpenAPI, "3.0")
+
+	templates := make([]string, 0, len(document.Paths))
+	for template := range document.Paths {
+		templates = append(templates, template)
+	}
+	sort.Strings(templates)
+
+	var errs RouteErrors
+	var routes []specRoute
+	used := make(map[string]bool)
+	checked := make(map[*Schema]bool)
+	for _, template := range templates {
+		item := document.Paths[template]
+		path, err := templateRoute(template)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, method := range []string{MethodGet, MethodPut, MethodPost, MethodDelete, MethodOptions, MethodHead, MethodPatch} {
+			operation := *item.operation(method)
+			if operation == nil {
+				continue
+			}
+			if operation.OperationID == "" {
+				errs = append(errs, fmt.Errorf("rou: operation %s %s does not have operationId", method, template))
+				continue
+			}
+			handler, ok := handlers[operation.OperationID]
+			if !ok {
+				errs = append(errs, fmt.Errorf("rou: handler of operation %q is not given", operation.OperationID))
+				continue
+			}
+			used[operation.OperationID] = true
+
+			parameters, err := resolveParameters(document, item, operation)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			for _, parameter := range parameters {
+				if err := checkReferences(document, parameter.Schema, checked); err != nil {
+					errs = append(errs, err)
+				}
+			}
+			if operation.RequestBody != nil {
+				for _, media := range operation.RequestBody.Content {
+					if err := checkReferences(document, media.Schema, checked); err != nil {
+						errs = append(errs, err)
+					}
+				}
+			}
+
+			routes = append(routes, specRoute{
+				method:    method,
+				path:      path,
+				operation: operation,
+				handler:   handler,
+				validator: &operationValidator{parameters: parameters, requestBody: operation.RequestBody, schemas: schemas, nullable: nullable},
+			})
+		}
+	}
+
+	var unused []string
+	for operationID := range handlers {
+		if !used[operationID] {
+			unused = append(unused, operationID)
+		}
+	}
+	sort.Strings(unused)
+	for _, operationID := range unused {
+		errs = append(errs, fmt.Errorf("rou: handler %q does not match any operation", operationID))
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	for _, route := range routes {
+		registered := r.Handle(route.method, route.path, route.handler)
+		registered.Wrap(route.validator.middleware)
+		registered.Describe(RouteDoc{
+			OperationID: route.operation.OperationID,
+			Summary:     route.operation.Summary,
+			Description: route.operation.Description,
+			Tags:        route.operation.Tags,
+			Deprecated:  route.operation.Deprecated,
+		})
+	}
+	return nil
+}
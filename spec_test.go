@@ -0,0 +1,323 @@
+package rou
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testSpec = `{
+	"openapi": "3.0.3",
+	"info": {"title": "Users", "version": "1.0.0"},
+	"paths": {
+		"/users/{id}": {
+			"parameters": [{"$ref": "#/components/parameters/UserID"}],
+			"get": {
+				"operationId": "getUser",
+				"parameters": [
+					{"name": "fields", "in": "query", "schema": {"type": "array", "items": {"type": "string", "enum": ["name", "email"]}}},
+					{"name": "X-Tenant", "in": "header", "required": true, "schema": {"type": "string", "format": "uuid"}}
+				]
+			},
+			"put": {
+				"operationId": "updateUser",
+				"requestBody": {
+					"required": true,
+					"content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}
+				}
+			}
+		}
+	},
+	"components": {
+		"parameters": {
+			"UserID": {"name": "id", "in": "path", "required": true, "schema": {"type": "integer", "minimum": 1}}
+		},
+		"schemas": {
+			"User": {
+				"type": "object",
+				"required": ["name"],
+				"additionalProperties": false,
+				"properties": {
+					"name": {"type": "string", "minLength": 2},
+					"email": {"type": "string", "nullable": true, "format": "email"},
+					"tags": {"type": "array", "maxItems": 2, "items": {"type": "string"}},
+					"address": {"$ref": "#/components/schemas/Address"}
+				}
+			},
+			"Address": {
+				"type": "object",
+				"properties": {"zip": {"type": "string", "pattern": "^[0-9]{5}$"}}
+			}
+		}
+	}
+}`
+
+func loadTestSpec(t *testing.T) *OpenAPIDocument {
+	document, err := LoadOpenAPI(strings.NewReader(testSpec))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return document
+}
+
+func TestRegisterOpenAPI(t *testing.T) {
+	router := NewRouter()
+	var body string
+	err := RegisterOpenAPI(router, loadTestSpec(t), map[string]func(*Context){
+		"getUser": func(ctx *Context) {
+			ctx.SuccessJSONResponse(ctx.RouterParams().Get("id"))
+		},
+		"updateUser": func(ctx *Context) {
+			data, _ := io.ReadAll(ctx.Request().Body)
+			body = string(data)
+			ctx.SuccessJSONResponse(nil)
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tenant := "7d444840-9dc0-11d1-b245-5ffdce74fad2"
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		header      http.Header
+		body        string
+		status      int
+		message     string
+		wantDetails []SchemaViolation
+	}{
+		{
+			name:   "valid get",
+			method: http.MethodGet, target: "/users/10?fields=name&fields=email",
+			header: http.Header{"X-Tenant": {tenant}},
+			status: http.StatusOK,
+		},
+		{
+			name:   "invalid params",
+			method: http.MethodGet, target: "/users/0?fields=phone",
+			status: http.StatusBadRequest, message: MessageRequestIsNotValid,
+			wantDetails: []SchemaViolation{
+				{In: "path", Path: "id", Message: "value should be at least 1"},
+				{In: "query", Path: "fields/0", Message: "value is not one of allowed values"},
+				{In: "header", Path: "X-Tenant", Message: "value is required"},
+			},
+		},
+		{
+			name:   "not integer",
+			method: http.MethodGet, target: "/users/abc",
+			header: http.Header{"X-Tenant": {tenant}},
+			status: http.StatusBadRequest, message: MessageRequestIsNotValid,
+			wantDetails: []SchemaViolation{{In: "path", Path: "id", Message: "expected integer, got string"}},
+		},
+		{
+			name:   "valid body",
+			method: http.MethodPut, target: "/users/10",
+			header: http.Header{"Content-Type": {"application/json; charset=utf-8"}},
+			body:   `{"name": "John", "email": null, "address": {"zip": "12345"}}`,
+			status: http.StatusOK,
+		},
+		{
+			name:   "invalid body",
+			method: http.MethodPut, target: "/users/10",
+			header: http.Header{"Content-Type": {"application/json"}},
+			body:   `{"email": "john", "tags": ["a", "b", 3], "address": {"zip": "1"}, "age": 10}`,
+			status: http.StatusBadRequest, message: MessageBodyIsNotValid,
+			wantDetails: []SchemaViolation{
+				{In: "body", Path: "/name", Message: "value is required"},
+				{In: "body", Path: "/address/zip", Message: `value does not match pattern "^[0-9]{5}$"`},
+				{In: "body", Path: "/age", Message: "property is not allowed"},
+				{In: "body", Path: "/email", Message: "value is not valid email"},
+				{In: "body", Path: "/tags", Message: "number of items should be at most 2"},
+				{In: "body", Path: "/tags/2", Message: "expected string, got integer"},
+			},
+		},
+		{
+			name:   "missing body",
+			method: http.MethodPut, target: "/users/10",
+			status: http.StatusBadRequest, message: MessageBodyIsNotValid,
+			wantDetails: []SchemaViolation{{In: "body", Path: "", Message: "body is required"}},
+		},
+		{
+			name:   "invalid JSON",
+			method: http.MethodPut, target: "/users/10",
+			header: http.Header{"Content-Type": {"application/json"}},
+			body:   `{"name":`,
+			status: http.StatusBadRequest, message: MessageBodyIsNotValid,
+			wantDetails: []SchemaViolation{{In: "body", Path: "", Message: "body is not valid JSON"}},
+		},
+		{
+			name:   "unsupported media type",
+			method: http.MethodPut, target: "/users/10",
+			header: http.Header{"Content-Type": {"text/plain"}},
+			body:   "John",
+			status: http.StatusUnsupportedMediaType, message: MessageUnsupportedMediaType,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest(test.method, test.target, strings.NewReader(test.body))
+			for name, values := range test.header {
+				r.Header[name] = values
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+
+			if w.Code != test.status {
+				t.Fatalf("Got status - %d, want - %d: %s", w.Code, test.status, w.Body.String())
+			}
+			if test.status == http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Error struct {
+					Message string            `json:"message"`
+					Details []SchemaViolation `json:"details"`
+				} `json:"error"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+			if response.Error.Message != test.message {
+				t.Errorf("Got message - %q, want - %q", response.Error.Message, test.message)
+			}
+			if !reflect.DeepEqual(response.Error.Details, test.wantDetails) {
+				t.Errorf("Got details - %+v, want - %+v", response.Error.Details, test.wantDetails)
+			}
+		})
+	}
+
+	if body != `{"name": "John", "email": null, "address": {"zip": "12345"}}` {
+		t.Errorf("expected body to be restored for handler, got %q", body)
+	}
+
+	document := router.OpenAPI(OpenAPIInfo{Title: "Users", Version: "1.0.0"})
+	if operation := document.Paths["/users/{id}"].Put; operation == nil || operation.OperationID != "updateUser" {
+		t.Errorf("expected registered operation in generated document, got %+v", operation)
+	}
+}
+
+func TestRegisterOpenAPIBodySize(t *testing.T) {
+	router := NewRouter()
+	router.MaxBodySize = 10
+	var called bool
+	err := RegisterOpenAPI(router, loadTestSpec(t), map[string]func(*Context){
+		"getUser":    func(ctx *Context) {},
+		"updateUser": func(ctx *Context) { called = true },
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	r := httptest.NewRequest(http.MethodPut, "/users/10", strings.NewReader(`{"name": "`+strings.Repeat("a", 1000)+`"}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if w.Code != http.StatusRequestEntityTooLarge || called {
+		t.Errorf("Got - %d %s, want - %d", w.Code, w.Body.String(), http.StatusRequestEntityTooLarge)
+	}
+}
+
+func TestOpenAPINullable(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		schema  string
+		status  int
+	}{
+		{name: "3.0 nullable", version: "3.0.3", schema: `{"type": "string", "nullable": true}`, status: http.StatusOK},
+		{name: "3.0 not nullable", version: "3.0.3", schema: `{"type": "string"}`, status: http.StatusBadRequest},
+		{name: "3.1 null type", version: "3.1.0", schema: `{"type": ["string", "null"]}`, status: http.StatusOK},
+		{name: "3.1 ignores nullable", version: "3.1.0", schema: `{"type": "string", "nullable": true}`, status: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			document, err := LoadOpenAPI(strings.NewReader(`{
+				"openapi": "` + test.version + `",
+				"paths": {"/users": {"post": {
+					"operationId": "createUser",
+					"requestBody": {"content": {"application/json": {"schema": {
+						"type": "object",
+						"properties": {"email": ` + test.schema + `}
+					}}}}
+				}}}
+			}`))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			router := NewRouter()
+			if err := RegisterOpenAPI(router, document, map[string]func(*Context){"createUser": func(ctx *Context) {}}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			r := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"email": null}`))
+			r.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+			if w.Code != test.status {
+				t.Errorf("Got - %d %s, want - %d", w.Code, w.Body.String(), test.status)
+			}
+		})
+	}
+}
+
+func TestRegisterOpenAPIErrors(t *testing.T) {
+	document := loadTestSpec(t)
+	document.Paths["/files/{name}.txt"] = &OpenAPIPathItem{Get: &OpenAPIOperation{OperationID: "getFile"}}
+	document.Paths["/orders"] = &OpenAPIPathItem{
+		Get: &OpenAPIOperation{},
+		Post: &OpenAPIOperation{OperationID: "createOrder", RequestBody: &OpenAPIRequestBody{
+			Content: map[string]*OpenAPIMediaType{"application/json": {Schema: &Schema{Ref: "#/components/schemas/Order"}}},
+		}},
+	}
+
+	router := NewRouter()
+	err := RegisterOpenAPI(router, document, map[string]func(*Context){
+		"getUser":     func(ctx *Context) {},
+		"createOrder": func(ctx *Context) {},
+		"deleteUser":  func(ctx *Context) {},
+	})
+
+	var errs RouteErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected RouteErrors, got %v", err)
+	}
+	want := []string{
+		`rou: path "/files/{name}.txt": only whole segments can be templated`,
+		"rou: operation GET /orders does not have operationId",
+		`rou: unknown schema reference "#/components/schemas/Order"`,
+		`rou: handler of operation "updateUser" is not given`,
+		`rou: handler "deleteUser" does not match any operation`,
+	}
+	var got []string
+	for _, err := range errs {
+		got = append(got, err.Error())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got - %q, want - %q", got, want)
+	}
+	if len(router.Routes.list) != 0 {
+		t.Errorf("expected no routes to be registered, got %d", len(router.Routes.list))
+	}
+}
+
+func TestLoadOpenAPI(t *testing.T) {
+	if _, err := LoadOpenAPI(strings.NewReader(`{"openapi": "2.0"}`)); err == nil {
+		t.Error("expected error for unsupported version")
+	}
+	if _, err := LoadOpenAPI(strings.NewReader(`{`)); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+	if _, err := LoadOpenAPIFile("not-found.json"); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
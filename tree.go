@@ -0,0 +1,331 @@
+package rou
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+type nodeKind uint8
+
+const (
+	staticNode nodeKind = iota
+	paramNode
+	catchAllNode
+)
+
+// Single vertex of the compressed prefix tree used to match request paths.
+//
+// Static nodes hold a fragment of the path which may be shared by several routes,
+// param nodes always match one whole path segment and catch-all nodes match the rest of the path.
+//
+// Param and catch-all nodes store the parameter name and the first route which created them in path and owner.
+// Names of params are not used for matching, every route keeps its own names in Route.paramNames,
+// so "/users/:id" and "/users/:name/posts" share the same param node.
+// Params with different constraints are stored in different nodes.
+type node struct {
+	kind       nodeKind
+	path       string
+	indices    []byte
+	statics    []*node
+	params     []*node
+	catchAll   *node
+	route      *Route
+	owner      *Route
+	expression string
+	constraint Constraint
+}
+
+// Compressed prefix tree of routes registered for one HTTP method
+type tree struct {
+	method      string
+	root        *node
+	maxParams   int
+	ambiguous   []*RouteConflictError
+	constraints map[string]Constraint
+}
+
+func newTree(method string, constraints map[string]Constraint) *tree {
+	return &tree{method: method, root: &node{}, constraints: constraints}
+}
+
+// Returns path without leading and trailing slashes, the form in which paths are stored in the tree
+func routeKey(path string) string {
+	return strings.Trim(path, "/")
+}
+
+// Returns the length of the common prefix of two strings
+func commonPrefix(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+// Returns the index of the next segment which starts with a parameter or a catch-all
+// or the length of the path if there is no such segment
+func nextParamSegment(path string) int {
+	for i := 1; i < len(path); i++ {
+		if (path[i] == ':' || path[i] == '*') && path[i-1] == '/' {
+			return i
+		}
+	}
+	return len(path)
+}
+
+// Checks syntax of the route path
+func validateRoutePath(path string) string {
+	segments := strings.Split(routeKey(path), "/")
+	for i, segment := range segments {
+		if segment == "" {
+			continue
+		}
+		if segment[0] == ':' {
+			if name, _ := parseParam(segment); name == "" {
+				return "param name should not be empty"
+			}
+		}
+		if segment[0] == '*' && len(segment) == 1 {
+			return "param name should not be empty"
+		}
+		if segment[0] == '*' && i != len(segments)-1 {
+			return "catch-all segment must be the last one"
+		}
+	}
+	return ""
+}
+
+// Inserts route into the tree.
+//
+// Returns a conflict if the route can not be stored, e.g. there is a route with the same path.
+// Ambiguous conflicts do not prevent the route from being stored, they are collected in t.ambiguous.
+func (t *tree) insert(route *Route) *RouteConflictError {
+	if reason := validateRoutePath(route.Path); reason != "" {
+		return &RouteConflictError{Method: t.method, Path: route.Path, Location: route.location, Reason: reason}
+	}
+
+	n := t.root
+	var paramNames []string
+	var ambiguous []*RouteConflictError
+
+	path := routeKey(route.Path)
+	for path != "" {
+		if path[0] == '*' {
+			name := path[1:]
+			n = n.addCatchAll(name, route)
+			if n.path != name {
+				ambiguous = append(ambiguous, t.conflict(route, n.owner, fmt.Sprintf("catch-all %q is registered as %q", name, n.path), true))
+			}
+			paramNames = append(paramNames, name)
+			break
+		}
+
+		if path[0] == ':' {
+			end := strings.IndexByte(path, '/')
+			if end < 0 {
+				end = len(path)
+			}
+			name, expression := parseParam(path[:end])
+			constraint, err := resolveConstraint(t.constraints, expression)
+			if err != nil {
+				return &RouteConflictError{Method: t.method, Path: route.Path, Location: route.location, Reason: "invalid constraint: " + err.Error()}
+			}
+			n = n.addParam(name, expression, constraint, t.constraints, route)
+			if n.path != name {
+				ambiguous = append(ambiguous, t.conflict(route, n.owner, fmt.Sprintf("param %q is registered as %q at the same position", name, n.path), true))
+			}
+			paramNames = append(paramNames, name)
+			path = path[end:]
+			continue
+		}
+
+		end := nextParamSegment(path)
+		n = n.addStatic(path[:end])
+		path = path[end:]
+	}
+
+	if n.route != nil {
+		return t.conflict(route, n.route, "both routes match the same requests", false)
+	}
+	n.route = route
+	route.paramNames = paramNames
+	route.paramSegments = paramSegments(route.Path)
+	if len(paramNames) > t.maxParams {
+		t.maxParams = len(paramNames)
+	}
+	t.ambiguous = append(t.ambiguous, ambiguous...)
+	return nil
+}
+
+// Returns indexes of segments with params in the route path
+func paramSegments(path string) []int {
+	var indexes []int
+	for i, segment := range strings.Split(routeKey(path), "/") {
+		if segment != "" && (segment[0] == ':' || segment[0] == '*') {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+func (t *tree) conflict(route, existing *Route, reason string, ambiguous bool) *RouteConflictError {
+	return &RouteConflictError{
+		Method:           t.method,
+		Path:             route.Path,
+		Location:         route.location,
+		ExistingPath:     existing.Path,
+		ExistingLocation: existing.location,
+		Reason:           reason,
+		Ambiguous:        ambiguous,
+	}
+}
+
+// Finds route for the request path.
+//
+// Values of route params are returned in the same order as route.paramNames.
+func (t *tree) lookup(path string) (*Route, []string) {
+	values := paramValues{max: t.maxParams}
+	route := t.root.match(routeKey(path), &values)
+	if route == nil {
+		return nil, nil
+	}
+	return route, values.list
+}
+
+// Inserts static fragment of the path below the node splitting existing nodes if needed.
+// Returns the node in which the fragment ends.
+func (n *node) addStatic(path string) *node {
+	for path != "" {
+		idx := bytes.IndexByte(n.indices, path[0])
+		if idx < 0 {
+			child := &node{path: path}
+			n.indices = append(n.indices, path[0])
+			n.statics = append(n.statics, child)
+			return child
+		}
+
+		child := n.statics[idx]
+		common := commonPrefix(path, child.path)
+		if common < len(child.path) {
+			rest := *child
+			rest.path = child.path[common:]
+			*child = node{
+				path:    child.path[:common],
+				indices: []byte{rest.path[0]},
+				statics: []*node{&rest},
+			}
+		}
+		n = child
+		path = path[common:]
+	}
+	return n
+}
+
+// Returns param child of the node with the same constraint creating it if it does not exist.
+//
+// Params with constraints are placed before the param without constraint and ordered by constraintBefore,
+// so the order does not depend on order of registration.
+func (n *node) addParam(name, expression string, constraint Constraint, constraints map[string]Constraint, owner *Route) *node {
+	for _, child := range n.params {
+		if child.expression == expression {
+			return child
+		}
+	}
+
+	child := &node{kind: paramNode, path: name, owner: owner, expression: expression}
+	if expression == "" {
+		n.params = append(n.params, child)
+		return child
+	}
+
+	child.constraint = constraint
+	i := 0
+	for i < len(n.params) && n.params[i].expression != "" && constraintBefore(constraints, n.params[i].expression, expression) {
+		i++
+	}
+	n.params = append(n.params, nil)
+	copy(n.params[i+1:], n.params[i:])
+	n.params[i] = child
+	return child
+}
+
+// Returns catch-all child of the node creating it if it does not exist.
+//
+// There is only one catch-all child per node.
+func (n *node) addCatchAll(name string, owner *Route) *node {
+	if n.catchAll == nil {
+		n.catchAll = &node{kind: catchAllNode, path: name, owner: owner}
+	}
+	return n.catchAll
+}
+
+// Matches the rest of the path against children of the node.
+//
+// Priority does not depend on order of registration: static child is checked first, then param children
+// and the catch-all child is the last one. If a branch does not lead to a route the next one is tried,
+// so the priority is applied segment by segment.
+//
+// Params with constraints are checked before the param without constraint: named constraints first,
+// then regular expressions, each kind in lexical order of constraints. Value which does not satisfy
+// the constraint falls through to the next param.
+func (n *node) match(path string, values *paramValues) *Route {
+	if path == "" {
+		return n.route
+	}
+
+	if idx := bytes.IndexByte(n.indices, path[0]); idx >= 0 {
+		child := n.statics[idx]
+		if strings.HasPrefix(path, child.path) {
+			if route := child.match(path[len(child.path):], values); route != nil {
+				return route
+			}
+		}
+	}
+
+	if len(n.params) > 0 {
+		end := strings.IndexByte(path, '/')
+		if end < 0 {
+			end = len(path)
+		}
+		if end > 0 {
+			value := path[:end]
+			for _, child := range n.params {
+				if child.constraint != nil && !child.constraint(value) {
+					continue
+				}
+				values.push(value)
+				if route := child.match(path[end:], values); route != nil {
+					return route
+				}
+				values.pop()
+			}
+		}
+	}
+
+	if n.catchAll != nil && n.catchAll.route != nil {
+		values.push(path)
+		return n.catchAll.route
+	}
+	return nil
+}
+
+// Values of route params collected while walking the tree.
+//
+// The list is allocated once with enough capacity for the longest route,
+// so static routes do not allocate at all.
+type paramValues struct {
+	list []string
+	max  int
+}
+
+func (p *paramValues) push(value string) {
+	if p.list == nil {
+		p.list = make([]string, 0, p.max)
+	}
+	p.list = append(p.list, value)
+}
+
+func (p *paramValues) pop() {
+	p.list = p.list[:len(p.list)-1]
+}
@@ -0,0 +1,218 @@
+package rou
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTreeLookup(t *testing.T) {
+	paths := []string{
+		"/",
+		"/users",
+		"/users/:id",
+		"/users/:id/posts",
+		"/users/:id/posts/:postId",
+		"/useful/links",
+		"/posts/recent",
+		"/posts/:id",
+	}
+
+	methodTree := newTree(MethodGet, defaultConstraints())
+	for _, path := range paths {
+		if err := methodTree.insert(&Route{Path: path}); err != nil {
+			t.Fatalf("route %q is not stored: %v", path, err)
+		}
+	}
+
+	tests := []struct {
+		requestPath string
+		route       string
+		values      []string
+	}{
+		{requestPath: "/", route: "/"},
+		{requestPath: "/users", route: "/users"},
+		{requestPath: "/users/", route: "/users"},
+		{requestPath: "/users/10", route: "/users/:id", values: []string{"10"}},
+		{requestPath: "/users/10/posts", route: "/users/:id/posts", values: []string{"10"}},
+		{requestPath: "/users/10/posts/45", route: "/users/:id/posts/:postId", values: []string{"10", "45"}},
+		{requestPath: "/useful/links", route: "/useful/links"},
+		{requestPath: "/posts/recent", route: "/posts/recent"},
+		{requestPath: "/posts/recently", route: "/posts/:id", values: []string{"recently"}},
+		{requestPath: "/use"},
+		{requestPath: "/users/10/comments"},
+		{requestPath: "/users//posts"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.requestPath, func(t *testing.T) {
+			route, values := methodTree.lookup(test.requestPath)
+			if test.route == "" {
+				if route != nil {
+					t.Errorf("expected no route, got %q", route.Path)
+				}
+				return
+			}
+			if route == nil {
+				t.Fatalf("route is not found. Want - %q", test.route)
+			}
+			if route.Path != test.route {
+				t.Errorf("wrong route. Got - %q, want - %q", route.Path, test.route)
+			}
+			if !reflect.DeepEqual(values, test.values) {
+				t.Errorf("wrong params. Got - %v, want - %v", values, test.values)
+			}
+		})
+	}
+}
+
+func TestTreeLookupAllocations(t *testing.T) {
+	methodTree := newTree(MethodGet, defaultConstraints())
+	for _, path := range benchmarkPaths() {
+		methodTree.insert(&Route{Path: path})
+	}
+
+	t.Run("static route", func(t *testing.T) {
+		allocs := testing.AllocsPerRun(100, func() {
+			methodTree.lookup("/resource-42/list")
+		})
+		if allocs != 0 {
+			t.Errorf("expected no allocations, got %v", allocs)
+		}
+	})
+
+	t.Run("route with params", func(t *testing.T) {
+		allocs := testing.AllocsPerRun(100, func() {
+			methodTree.lookup("/resource-42/10/items/20")
+		})
+		if allocs > 1 {
+			t.Errorf("expected at most one allocation, got %v", allocs)
+		}
+	})
+}
+
+// Generates ~600 routes which looks like a typical REST API
+func benchmarkPaths() []string {
+	var paths []string
+	for i := 0; i < 100; i++ {
+		resource := fmt.Sprintf("/resource-%d", i)
+		paths = append(paths,
+			resource,
+			resource+"/list",
+			resource+"/:id",
+			resource+"/:id/items",
+			resource+"/:id/items/:itemId",
+			resource+"/:id/items/:itemId/history",
+		)
+	}
+	return paths
+}
+
+// Route matching as it was implemented before the tree: linear scan which splits paths into chunks
+func linearMatch(routes []*Route, requestPath string) (*Route, map[string]string) {
+	for _, route := range routes {
+		params := make(map[string]string)
+		if route.Path == requestPath {
+			return route, params
+		}
+
+		routeChunks := strings.Split(strings.Trim(route.Path, "/"), "/")
+		requestChunks := strings.Split(strings.Trim(requestPath, "/"), "/")
+		if len(routeChunks) != len(requestChunks) {
+			continue
+		}
+
+		equal := true
+		for i, chunk := range routeChunks {
+			if chunk[0] == ':' {
+				params[chunk[1:]] = requestChunks[i]
+			} else if chunk != requestChunks[i] {
+				equal = false
+				break
+			}
+		}
+		if equal {
+			return route, params
+		}
+	}
+	return nil, nil
+}
+
+var benchmarkRequests = []struct {
+	name string
+	path string
+}{
+	{name: "static first", path: "/resource-0"},
+	{name: "static last", path: "/resource-99/list"},
+	{name: "one param", path: "/resource-50/10"},
+	{name: "two params", path: "/resource-99/10/items/20/history"},
+	{name: "not found", path: "/unknown/path"},
+}
+
+func BenchmarkLookup(b *testing.B) {
+	paths := benchmarkPaths()
+
+	methodTree := newTree(MethodGet, defaultConstraints())
+	routes := make([]*Route, 0, len(paths))
+	for _, path := range paths {
+		route := &Route{Path: path}
+		methodTree.insert(route)
+		routes = append(routes, route)
+	}
+
+	for _, request := range benchmarkRequests {
+		b.Run("tree/"+request.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				methodTree.lookup(request.path)
+			}
+		})
+
+		b.Run("linear/"+request.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				linearMatch(routes, request.path)
+			}
+		})
+	}
+}
+
+func BenchmarkServeHTTP(b *testing.B) {
+	router := NewRouter()
+	for _, path := range benchmarkPaths() {
+		router.Get(path, func(ctx *Context) {})
+	}
+
+	withMiddlewares := NewRouter()
+	next := func(next Handler) Handler {
+		return func(ctx *Context) { next(ctx) }
+	}
+	withMiddlewares.Wrap(next, next)
+	api := withMiddlewares.Group("/", func(g *Group) { g.Wrap(next) })
+	for _, path := range benchmarkPaths() {
+		api.Get(path, func(ctx *Context) {}).Wrap(next)
+	}
+
+	for _, request := range benchmarkRequests {
+		b.Run(request.name, func(b *testing.B) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, request.path, nil)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				router.ServeHTTP(w, r)
+			}
+		})
+
+		b.Run("middlewares/"+request.name, func(b *testing.B) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, request.path, nil)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				withMiddlewares.ServeHTTP(w, r)
+			}
+		})
+	}
+}
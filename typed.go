@@ -0,0 +1,106 @@
+package rou
+
+import (
+	"net/http"
+	"reflect"
+)
+
+// Returns TRUE if the request has body which should be decoded
+func hasBody(r *http.Request) bool {
+	return r.Body != nil && r.Body != http.NoBody && r.ContentLength != 0
+}
+
+// Decodes fields of dst from body if the request has it and from route params, query params and headers
+// by "param", "query" and "header" tags, then validates dst.
+//
+// Fields bound from route params, query params and headers are reset after the body is decoded,
+// so keys of the body can not override them.
+func (c Context) bindRequest(dst any) error {
+	tag := "json"
+	if hasBody(c.Request()) {
+		var err error
+		if tag, err = c.bindBody(dst); err != nil {
+			return err
+		}
+	}
+
+	if value := reflect.ValueOf(dst).Elem(); value.Kind() == reflect.Struct {
+		resetRequestFields(value)
+		for _, bind := range []func(any) error{c.bindParams, c.bindQuery, c.bindHeader} {
+			if err := bind(dst); err != nil {
+				return err
+			}
+		}
+	}
+	return c.validate(dst, tag, MessageRequestIsNotValid)
+}
+
+// Sets zero values to fields which are bound from route params, query params or headers, including fields of embedded structs
+func resetRequestFields(value reflect.Value) {
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldValue := value.Field(i)
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			if field.Type.Kind() == reflect.Struct {
+				resetRequestFields(fieldValue)
+			} else if field.Type.Kind() == reflect.Pointer && field.Type.Elem().Kind() == reflect.Struct && !fieldValue.IsNil() {
+				resetRequestFields(fieldValue.Elem())
+			}
+			continue
+		}
+		if isRequestField(field) && fieldValue.CanSet() {
+			fieldValue.Set(reflect.Zero(field.Type))
+		}
+	}
+}
+
+// Converts typed function to Handler.
+//
+// Request is decoded from body by Context.Bind if the request has body and from route params, query params
+// and headers by "param", "query" and "header" tags, which can not be overridden by the body,
+// then it is validated by "validate" tags.
+// Response is written in Envelope of the router with status 200, errors of decoding and errors returned by fn
+// are rendered by ErrorHandler of the router.
+//
+//	type GetUserRequest struct {
+//		ID int `param:"id" validate:"min=1"`
+//	}
+//
+//	router.Get("/users/:id", rou.JSON(func(ctx *rou.Context, request GetUserRequest) (User, error) {
+//		return findUser(request.ID)
+//	}))
+func JSON[Req any, Resp any](fn func(ctx *Context, request Req) (Resp, error)) Handler {
+	return wrapped(func(ctx *Context) {
+		var request Req
+		target := any(&request)
+		if t := reflect.TypeOf(target).Elem(); t.Kind() == reflect.Pointer {
+			request = reflect.New(t.Elem()).Interface().(Req)
+			target = request
+		}
+
+		if err := ctx.bindRequest(target); err != nil {
+			ctx.Error(err)
+			return
+		}
+		response, err := fn(ctx, request)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		ctx.JSONResponse(http.StatusOK, response)
+	}, fn)
+}
+
+// Registers typed function converted by JSON as handler of the route.
+//
+// Types of request and response are stored in the route and describe it in OpenAPI document
+// if they are not set by RouteDoc.
+func HandleJSON[Req any, Resp any](r Registrar, method string, route string, fn func(ctx *Context, request Req) (Resp, error)) RouterMethods {
+	registered := r.Handle(method, route, JSON(fn))
+	if stored, ok := registered.(*Route); ok {
+		stored.requestType = reflect.TypeOf((*Req)(nil)).Elem()
+		stored.responseType = reflect.TypeOf((*Resp)(nil)).Elem()
+	}
+	return registered
+}
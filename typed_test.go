@@ -0,0 +1,147 @@
+package rou
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type typedRequest struct {
+	ID      int    `param:"id" validate:"min=1"`
+	Verbose bool   `query:"verbose"`
+	Tenant  string `header:"X-Tenant"`
+	Name    string `json:"name" validate:"omitempty,min=2"`
+}
+
+type typedResponse struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Verbose bool   `json:"verbose"`
+	Tenant  string `json:"tenant"`
+}
+
+func typedHandler(ctx *Context, request typedRequest) (typedResponse, error) {
+	if request.ID == 404 {
+		return typedResponse{}, NewHTTPError(http.StatusNotFound, MessagePageNotFound)
+	}
+	return typedResponse{ID: request.ID, Name: request.Name, Verbose: request.Verbose, Tenant: request.Tenant}, nil
+}
+
+func TestJSON(t *testing.T) {
+	router := NewRouter()
+	HandleJSON(router, MethodGet, "/users/:id", typedHandler)
+	HandleJSON(router, MethodPut, "/users/:id", typedHandler)
+	router.Post("/items", JSON(func(ctx *Context, request *[]string) (int, error) {
+		if request == nil {
+			return 0, errors.New("nil request")
+		}
+		return len(*request), nil
+	}))
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		status int
+		want   string
+	}{
+		{
+			name: "params, query and header", method: http.MethodGet, target: "/users/10?verbose=true",
+			status: http.StatusOK,
+			want:   `{"error":null,"body":{"id":10,"name":"","verbose":true,"tenant":"acme"}}`,
+		},
+		{
+			name: "body", method: http.MethodPut, target: "/users/10", body: `{"name":"John"}`,
+			status: http.StatusOK,
+			want:   `{"error":null,"body":{"id":10,"name":"John","verbose":false,"tenant":"acme"}}`,
+		},
+		{
+			name: "body can not override params", method: http.MethodPut, target: "/users/10",
+			body:   `{"name":"John","id":999,"ID":999,"verbose":true,"Tenant":"evil"}`,
+			status: http.StatusOK,
+			want:   `{"error":null,"body":{"id":10,"name":"John","verbose":false,"tenant":"acme"}}`,
+		},
+		{
+			name: "invalid param", method: http.MethodGet, target: "/users/abc",
+			status: http.StatusBadRequest,
+			want:   `{"error":{"message":"Request is not valid","code":400,"details":[{"in":"path","path":"id","message":"expected integer, got \"abc\""}]},"body":null}`,
+		},
+		{
+			name: "validation", method: http.MethodPut, target: "/users/0", body: `{"name":"J"}`,
+			status: http.StatusBadRequest,
+			want:   `{"error":{"message":"Request is not valid","code":400,"details":[{"path":"/id","rule":"min","message":"value should be at least 1"},{"path":"/name","rule":"min","message":"length should be at least 2"}]},"body":null}`,
+		},
+		{
+			name: "invalid body", method: http.MethodPut, target: "/users/10", body: `{"name":`,
+			status: http.StatusBadRequest,
+			want:   `{"error":{"message":"Request body is not valid","code":400},"body":null}`,
+		},
+		{
+			name: "handler error", method: http.MethodGet, target: "/users/404",
+			status: http.StatusNotFound,
+			want:   `{"error":{"message":"Page not found","code":404},"body":null}`,
+		},
+		{
+			name: "pointer to slice", method: http.MethodPost, target: "/items", body: `["a","b"]`,
+			status: http.StatusOK,
+			want:   `{"error":null,"body":2}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest(test.method, test.target, strings.NewReader(test.body))
+			r.Header.Set("Content-Type", "application/json")
+			r.Header.Set("X-Tenant", "acme")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+
+			if w.Code != test.status || w.Body.String() != test.want {
+				t.Errorf("Got - %d %s, want - %d %s", w.Code, w.Body.String(), test.status, test.want)
+			}
+		})
+	}
+}
+
+func TestHandleJSONOpenAPI(t *testing.T) {
+	router := NewRouter()
+	HandleJSON(router, MethodGet, "/users/:id", typedHandler)
+	HandleJSON(router, MethodPut, "/users/:id", typedHandler).Describe(RouteDoc{Summary: "Update user"})
+	HandleJSON(router.Group("/v2"), MethodGet, "/users/:id", func(ctx *Context, request struct {
+		ID int `param:"id"`
+	}) (typedResponse, error) {
+		return typedResponse{}, nil
+	})
+
+	document := router.OpenAPI(OpenAPIInfo{Title: "Users", Version: "1.0.0"})
+
+	get := document.Paths["/users/{id}"].Get
+	if get.RequestBody != nil {
+		t.Error("expected no request body of GET handler")
+	}
+	var in []string
+	for _, parameter := range get.Parameters {
+		in = append(in, parameter.In+":"+parameter.Name)
+	}
+	if strings.Join(in, ",") != "path:id,query:verbose,header:X-Tenant" {
+		t.Errorf("Got params - %v", in)
+	}
+	put := document.Paths["/users/{id}"].Put
+	if put.Summary != "Update user" || put.RequestBody == nil {
+		t.Fatalf("expected description and types of typed handler, got %+v", put)
+	}
+	body := put.RequestBody.Content["application/json"].Schema
+	if properties := document.Components.Schemas["typedRequest"].Properties; body.Ref == "" || len(properties) != 1 || properties["name"] == nil {
+		t.Errorf("expected only body fields in request schema, got %+v", properties)
+	}
+	if response := get.Responses["200"].Content["application/json"].Schema.Properties["body"]; response.Ref != "#/components/schemas/typedResponse" {
+		t.Errorf("Got response schema - %+v", response)
+	}
+
+	if v2 := document.Paths["/v2/users/{id}"].Get; len(v2.Parameters) != 1 || v2.Responses["200"].Content == nil {
+		t.Errorf("Got operation - %+v", v2)
+	}
+}
@@ -0,0 +1,121 @@
+package rou
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Sets name of the route which is used to build its URL by SimpleRouter.URL
+//
+// Name should be unique in the router, duplicated names are reported by SimpleRouter.Validate.
+func (r *Route) Name(name string) RouterMethods {
+	r.reportRejected("Name")
+	r.name = name
+	if r.routes == nil {
+		return r
+	}
+	if existing, ok := r.routes.names[name]; ok {
+		r.routes.errors = append(r.routes.errors, &RouteConflictError{
+			Method:           r.method,
+			Path:             r.Path,
+			Location:         r.location,
+			ExistingPath:     existing.Path,
+			ExistingLocation: existing.location,
+			Reason:           fmt.Sprintf("route name %q is already used", name),
+		})
+		return r
+	}
+	r.routes.names[name] = r
+	return r
+}
+
+// Returns path of the route by name including routes of mounted routers and routes in which the route is stored
+func (r *routes) namedPath(name string) (string, *routes, bool) {
+	if route, ok := r.names[name]; ok {
+		return route.Path, r, true
+	}
+	for _, m := range r.mounts {
+		subRouter, ok := m.handler.(*SimpleRouter)
+		if !ok {
+			continue
+		}
+		if path, owner, ok := subRouter.Routes.namedPath(name); ok {
+			return joinPaths(m.prefix, path), owner, true
+		}
+	}
+	return "", nil, false
+}
+
+// Builds URL path of the route by its name and params given as pairs of name and value
+//
+//	router.Get("/users/:id/files/*path", GET_FileHandler).Name("file")
+//	router.URL("file", "id", "10", "path", "docs/a b.txt") // "/users/10/files/docs/a%20b.txt"
+//
+// Values are escaped, slashes are kept only in catch-all segments.
+// Returns error if the route is not found, some params are not given, unknown or do not satisfy constraints.
+func (sr SimpleRouter) URL(name string, params ...string) (string, error) {
+	return sr.URLWithQuery(name, nil, params...)
+}
+
+// Builds URL of the route by its name and params like URL and appends encoded query values,
+// constraints of routes of mounted routers are resolved by constraints of their routers
+func (sr SimpleRouter) URLWithQuery(name string, query url.Values, params ...string) (string, error) {
+	routePath, owner, ok := sr.Routes.namedPath(name)
+	if !ok {
+		return "", fmt.Errorf("rou: route with name %q is not found", name)
+	}
+	if len(params)%2 != 0 {
+		return "", fmt.Errorf("rou: params of route %q should be pairs of name and value", name)
+	}
+
+	values := make(map[string]string, len(params)/2)
+	for i := 0; i < len(params); i += 2 {
+		values[params[i]] = params[i+1]
+	}
+
+	segments := strings.Split(routePath, "/")
+	for i, segment := range segments {
+		if segment == "" || (segment[0] != ':' && segment[0] != '*') {
+			continue
+		}
+
+		paramName, expression := segment[1:], ""
+		if segment[0] == ':' {
+			paramName, expression = parseParam(segment)
+		}
+		value, ok := values[paramName]
+		if !ok || value == "" {
+			return "", fmt.Errorf("rou: param %q of route %q is not given", paramName, name)
+		}
+		delete(values, paramName)
+
+		if segment[0] == '*' {
+			parts := strings.Split(value, "/")
+			for j, part := range parts {
+				parts[j] = url.PathEscape(part)
+			}
+			segments[i] = strings.Join(parts, "/")
+			continue
+		}
+
+		constraint, err := resolveConstraint(owner.constraints, expression)
+		if err != nil {
+			return "", err
+		}
+		if constraint != nil && !constraint(value) {
+			return "", fmt.Errorf("rou: value %q of param %q does not satisfy constraint %q", value, paramName, expression)
+		}
+		segments[i] = url.PathEscape(value)
+	}
+
+	for paramName := range values {
+		return "", fmt.Errorf("rou: route %q does not have param %q", name, paramName)
+	}
+
+	result := strings.Join(segments, "/")
+	if len(query) > 0 {
+		result += "?" + query.Encode()
+	}
+	return result, nil
+}
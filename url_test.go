@@ -0,0 +1,96 @@
+package rou
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestURL(t *testing.T) {
+	fakeHandler := func(ctx *Context) {}
+
+	router := NewRouter()
+	router.Get("/users", fakeHandler).Name("users")
+	router.Get("/users/:id<int>", fakeHandler).Name("user")
+	router.Get("/users/:id/posts/:slug", fakeHandler).Name("post")
+	router.Get("/files/*path", fakeHandler).Name("file")
+	router.Group("/api", func(api *Group) {
+		api.Get("/teams/:team", fakeHandler).Name("team")
+	})
+
+	subRouter := NewRouter()
+	subRouter.Get("/invoices/:id", fakeHandler).Name("invoice")
+	router.Mount("/billing", subRouter)
+
+	numbers := NewRouter()
+	numbers.RegisterConstraint("even", func(value string) bool {
+		return value != "" && strings.ContainsAny(value[len(value)-1:], "02468")
+	})
+	numbers.Get("/n/:n<even>", fakeHandler).Name("num")
+	router.Mount("/api", numbers)
+
+	tests := []struct {
+		name   string
+		route  string
+		query  url.Values
+		params []string
+		want   string
+	}{
+		{name: "static route", route: "users", want: "/users"},
+		{name: "route with params", route: "post", params: []string{"id", "10", "slug", "hello"}, want: "/users/10/posts/hello"},
+		{name: "escapes values", route: "post", params: []string{"id", "a/b", "slug", "hello world?"}, want: "/users/a%2Fb/posts/hello%20world%3F"},
+		{name: "catch-all keeps slashes", route: "file", params: []string{"path", "docs/a b.txt"}, want: "/files/docs/a%20b.txt"},
+		{name: "query", route: "users", query: url.Values{"page": {"2"}, "q": {"a&b"}}, want: "/users?page=2&q=a%26b"},
+		{name: "route in group", route: "team", params: []string{"team", "core"}, want: "/api/teams/core"},
+		{name: "route of mounted router", route: "invoice", params: []string{"id", "7"}, want: "/billing/invoices/7"},
+		{name: "value satisfies constraint", route: "user", params: []string{"id", "10"}, want: "/users/10"},
+		{name: "constraint of mounted router", route: "num", params: []string{"n", "4"}, want: "/api/n/4"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := router.URLWithQuery(test.route, test.query, test.params...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != test.want {
+				t.Errorf("Got - %q, want - %q", got, test.want)
+			}
+		})
+	}
+
+	errorTests := []struct {
+		name   string
+		route  string
+		params []string
+	}{
+		{name: "unknown route", route: "unknown"},
+		{name: "missing param", route: "post", params: []string{"id", "10"}},
+		{name: "empty param", route: "post", params: []string{"id", "10", "slug", ""}},
+		{name: "unknown param", route: "users", params: []string{"id", "10"}},
+		{name: "odd number of params", route: "user", params: []string{"id"}},
+		{name: "value does not satisfy constraint", route: "user", params: []string{"id", "melony"}},
+		{name: "value does not satisfy constraint of mounted router", route: "num", params: []string{"n", "3"}},
+	}
+
+	for _, test := range errorTests {
+		t.Run(test.name, func(t *testing.T) {
+			if got, err := router.URL(test.route, test.params...); err == nil {
+				t.Errorf("expected error, got %q", got)
+			}
+		})
+	}
+
+	t.Run("duplicated name", func(t *testing.T) {
+		router := NewRouter()
+		router.Get("/users", fakeHandler).Name("users")
+		router.Get("/people", fakeHandler).Name("users")
+
+		if router.Validate() == nil {
+			t.Error("expected error for duplicated name")
+		}
+		if got, _ := router.URL("users"); got != "/users" {
+			t.Errorf("first route should keep the name, got %q", got)
+		}
+	})
+}
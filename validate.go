@@ -0,0 +1,436 @@
+package rou
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Error of the field which does not satisfy the rule from "validate" tag
+type FieldError struct {
+	// JSON pointer to the field, e.g. "/items/0/name"
+	Path    string `json:"path"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// Errors of all fields which do not satisfy rules
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Checks value of the field with param of the rule, e.g. "3" for "min=3".
+// Returned error is used as message of FieldError.
+type Rule func(value reflect.Value, param string) error
+
+const (
+	ruleRequired  = "required"
+	ruleOmitempty = "omitempty"
+	ruleDive      = "dive"
+)
+
+// Validates structs by rules from "validate" tags:
+//
+//	type CreateUser struct {
+//		Name   string   `json:"name" validate:"required,min=2,max=64"`
+//		Email  string   `json:"email" validate:"omitempty,email"`
+//		Role   string   `json:"role" validate:"oneof=admin user"`
+//		Emails []string `json:"emails" validate:"max=5,dive,email"`
+//	}
+//
+// Rules after "dive" are applied to items of slices and maps. Nested structs, slices and maps of structs are validated too.
+type Validator struct {
+	mu    sync.RWMutex
+	rules map[string]Rule
+	// Parsed rules of struct types by tag which is used for names of fields
+	types sync.Map
+}
+
+// Creates validator with built-in rules: required, omitempty, min, max, len, oneof, email, url, uuid, alpha and numeric
+func NewValidator() *Validator {
+	return &Validator{rules: map[string]Rule{
+		"min":     ruleMin,
+		"max":     ruleMax,
+		"len":     ruleLen,
+		"oneof":   ruleOneOf,
+		"email":   stringRule("email", isEmail),
+		"url":     stringRule("URL", isURL),
+		"uuid":    stringRule("UUID", isUUID),
+		"alpha":   stringRule("alphabetic string", isAlpha),
+		"numeric": stringRule("numeric string", isDigits),
+	}}
+}
+
+// Adds rule which can be used in "validate" tags, rule with the same name is replaced
+func (v *Validator) RegisterRule(name string, rule Rule) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.rules[name] = rule
+}
+
+func (v *Validator) rule(name string) (Rule, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	rule, ok := v.rules[name]
+	return rule, ok
+}
+
+type fieldRule struct {
+	name  string
+	param string
+}
+
+// Rules of the field or items of the field
+type ruleSet struct {
+	rules     []fieldRule
+	required  bool
+	omitempty bool
+	// Rules which are applied to items of slices and maps
+	dive *ruleSet
+}
+
+type fieldRules struct {
+	index []int
+	name  string
+	ruleSet
+}
+
+// Parses rules of the tag, rules are separated by comma and have optional param after "="
+func parseRules(tag string) ruleSet {
+	var set ruleSet
+	current := &set
+	if tag == "" {
+		return set
+	}
+	for _, item := range strings.Split(tag, ",") {
+		name, param := item, ""
+		if idx := strings.IndexByte(item, '='); idx >= 0 {
+			name, param = item[:idx], item[idx+1:]
+		}
+		switch name {
+		case ruleDive:
+			current.dive = &ruleSet{}
+			current = current.dive
+		case ruleRequired:
+			current.required = true
+		case ruleOmitempty:
+			current.omitempty = true
+		default:
+			current.rules = append(current.rules, fieldRule{name, param})
+		}
+	}
+	return set
+}
+
+// Returns name of the field from the tag, "json" tag, tags of binding or name of the field
+func fieldName(field reflect.StructField, tag string) string {
+	for _, key := range []string{tag, "json", "param", "query", "header", "form"} {
+		if name := strings.Split(field.Tag.Get(key), ",")[0]; name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// Returns parsed rules of the struct type, they are cached by type and tag
+func (v *Validator) structRules(t reflect.Type, tag string) []fieldRules {
+	type key struct {
+		t   reflect.Type
+		tag string
+	}
+	if cached, ok := v.types.Load(key{t, tag}); ok {
+		return cached.([]fieldRules)
+	}
+
+	var fields []fieldRules
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Tag.Get("validate") == "-" {
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && field.Tag.Get(tag) == "" && field.Tag.Get("json") == "" {
+			for _, inner := range v.structRules(field.Type, tag) {
+				inner.index = append([]int{i}, inner.index...)
+				fields = append(fields, inner)
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		fields = append(fields, fieldRules{
+			index:   []int{i},
+			name:    fieldName(field, tag),
+			ruleSet: parseRules(field.Tag.Get("validate")),
+		})
+	}
+	v.types.Store(key{t, tag}, fields)
+	return fields
+}
+
+// Validates value by "validate" tags of structs, returns ValidationErrors if some fields are not valid
+func (v *Validator) Validate(value any) error {
+	return v.validate(value, "json")
+}
+
+// Validates value and names fields in paths by the tag
+func (v *Validator) validate(value any, tag string) error {
+	validation := validation{validator: v, tag: tag}
+	validation.nested(reflect.ValueOf(value), "")
+	if validation.err != nil {
+		return validation.err
+	}
+	if len(validation.errors) > 0 {
+		return validation.errors
+	}
+	return nil
+}
+
+type validation struct {
+	validator *Validator
+	tag       string
+	errors    ValidationErrors
+	// Error of rules which are not registered
+	err error
+}
+
+// Validates fields of structs inside of the value
+func (v *validation) nested(value reflect.Value, path string) {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		if value.Type() == timeType {
+			return
+		}
+		for _, field := range v.validator.structRules(value.Type(), v.tag) {
+			v.check(value.FieldByIndex(field.index), field.ruleSet, path+"/"+escapePointer(field.name))
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			v.nested(value.Index(i), path+"/"+strconv.Itoa(i))
+		}
+	case reflect.Map:
+		for _, key := range sortedKeys(value) {
+			v.nested(value.MapIndex(key), path+"/"+escapePointer(fmt.Sprint(key.Interface())))
+		}
+	}
+}
+
+// Checks the value and its items by rules and validates nested structs
+func (v *validation) check(value reflect.Value, set ruleSet, path string) {
+	if !v.apply(value, set, path) {
+		return
+	}
+	if set.dive == nil {
+		v.nested(value, path)
+		return
+	}
+
+	value = indirect(value)
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			v.check(value.Index(i), *set.dive, path+"/"+strconv.Itoa(i))
+		}
+	case reflect.Map:
+		for _, key := range sortedKeys(value) {
+			v.check(value.MapIndex(key), *set.dive, path+"/"+escapePointer(fmt.Sprint(key.Interface())))
+		}
+	}
+}
+
+// Returns keys of the map sorted by their string form, so errors are reported in the same order
+func sortedKeys(value reflect.Value) []reflect.Value {
+	keys := value.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+	return keys
+}
+
+// Returns value behind pointers and interfaces, it is invalid if some of them is nil
+func indirect(value reflect.Value) reflect.Value {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+		value = value.Elem()
+	}
+	return value
+}
+
+// Applies rules to the value, returns FALSE if the value is empty and should not be checked further
+func (v *validation) apply(value reflect.Value, set ruleSet, path string) bool {
+	if value.IsZero() {
+		if set.required {
+			v.errors = append(v.errors, FieldError{Path: path, Rule: ruleRequired, Message: "value is required"})
+			return false
+		}
+		if set.omitempty {
+			return false
+		}
+	}
+
+	value = indirect(value)
+	if !value.IsValid() {
+		return false
+	}
+	for _, item := range set.rules {
+		rule, ok := v.validator.rule(item.name)
+		if !ok {
+			v.err = fmt.Errorf("rou: unknown validation rule %q", item.name)
+			return false
+		}
+		if err := rule(value, item.param); err != nil {
+			v.errors = append(v.errors, FieldError{Path: path, Rule: item.name, Message: err.Error()})
+		}
+	}
+	return true
+}
+
+// Returns size of the value which is compared by min, max and len rules: length of strings, slices and maps or number
+func ruleSize(value reflect.Value, rule string, param string) (size float64, limit float64, length bool, err error) {
+	limit, err = strconv.ParseFloat(param, 64)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("invalid param %q of rule %q", param, rule)
+	}
+	switch value.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(value.String())), limit, true, nil
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(value.Len()), limit, true, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), limit, false, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(value.Uint()), limit, false, nil
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), limit, false, nil
+	}
+	return 0, 0, false, fmt.Errorf("rule %q is not supported by type %s", rule, value.Type())
+}
+
+func ruleMin(value reflect.Value, param string) error {
+	size, limit, length, err := ruleSize(value, "min", param)
+	switch {
+	case err != nil:
+		return err
+	case size >= limit:
+		return nil
+	case length:
+		return fmt.Errorf("length should be at least %s", param)
+	}
+	return fmt.Errorf("value should be at least %s", param)
+}
+
+func ruleMax(value reflect.Value, param string) error {
+	size, limit, length, err := ruleSize(value, "max", param)
+	switch {
+	case err != nil:
+		return err
+	case size <= limit:
+		return nil
+	case length:
+		return fmt.Errorf("length should be at most %s", param)
+	}
+	return fmt.Errorf("value should be at most %s", param)
+}
+
+func ruleLen(value reflect.Value, param string) error {
+	size, limit, length, err := ruleSize(value, "len", param)
+	switch {
+	case err != nil:
+		return err
+	case !length:
+		return fmt.Errorf("rule %q is not supported by type %s", "len", value.Type())
+	case size != limit:
+		return fmt.Errorf("length should be %s", param)
+	}
+	return nil
+}
+
+// Checks that value is one of values separated by spaces
+func ruleOneOf(value reflect.Value, param string) error {
+	actual := fmt.Sprint(value.Interface())
+	allowed := strings.Fields(param)
+	for _, item := range allowed {
+		if item == actual {
+			return nil
+		}
+	}
+	return fmt.Errorf("value should be one of %s", strings.Join(allowed, ", "))
+}
+
+// Returns rule which checks strings by the function
+func stringRule(description string, check func(string) bool) Rule {
+	return func(value reflect.Value, param string) error {
+		if value.Kind() != reflect.String {
+			return fmt.Errorf("value should be a string")
+		}
+		if !check(value.String()) {
+			return fmt.Errorf("value is not valid %s", description)
+		}
+		return nil
+	}
+}
+
+func isEmail(value string) bool {
+	address, err := mail.ParseAddress(value)
+	return err == nil && address.Address == value
+}
+
+func isURL(value string) bool {
+	parsed, err := url.ParseRequestURI(value)
+	return err == nil && parsed.Scheme != "" && parsed.Host != ""
+}
+
+var defaultValidator = NewValidator()
+
+// Returns Validator of the router or validator with built-in rules
+func (c Context) validator() *Validator {
+	if c.router != nil && c.router.Validator != nil {
+		return c.router.Validator
+	}
+	return defaultValidator
+}
+
+// Validates value by Validator of the router and names fields in paths by the tag
+func (c Context) validate(value any, tag string, message string) error {
+	err := c.validator().validate(value, tag)
+	var errs ValidationErrors
+	if errors.As(err, &errs) {
+		return NewHTTPError(http.StatusBadRequest, message).WithDetails(errs).Wrap(errs)
+	}
+	return err
+}
+
+// Validates value by "validate" tags with Validator of the router.
+//
+// Returns HTTPError with status 400, MessageBodyIsNotValid and ValidationErrors in details if some fields are not valid.
+func (c Context) Validate(value any) error {
+	return c.validate(value, "json", MessageBodyIsNotValid)
+}
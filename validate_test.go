@@ -0,0 +1,162 @@
+package rou
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type validateAddress struct {
+	City string `json:"city" validate:"required"`
+	Zip  string `json:"zip" validate:"len=5,numeric"`
+}
+
+type validateAudit struct {
+	CreatedBy string `json:"created_by" validate:"required"`
+}
+
+type validateUser struct {
+	validateAudit
+	Name      string             `json:"name" validate:"required,min=2,max=8"`
+	Age       int                `json:"age" validate:"min=18,max=130"`
+	Email     string             `json:"email,omitempty" validate:"omitempty,email"`
+	Role      string             `json:"role" validate:"oneof=admin user"`
+	Website   *string            `json:"website" validate:"omitempty,url"`
+	Emails    []string           `json:"emails" validate:"max=2,dive,required,email"`
+	Addresses []validateAddress  `json:"addresses" validate:"required"`
+	Labels    map[string]string  `json:"labels" validate:"dive,alpha"`
+	Contacts  map[string]*string `json:"contacts"`
+	Ignored   string             `json:"ignored" validate:"-"`
+	private   string
+}
+
+func TestValidator(t *testing.T) {
+	website := "not a url"
+	user := validateUser{
+		Name:      "J",
+		Age:       10,
+		Email:     "john",
+		Role:      "guest",
+		Website:   &website,
+		Emails:    []string{"a@example.com", "", "b"},
+		Addresses: []validateAddress{{City: "Paris", Zip: "75001"}, {Zip: "1a"}},
+		Labels:    map[string]string{"b": "x1", "a": "ok"},
+	}
+
+	err := NewValidator().Validate(&user)
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+
+	want := ValidationErrors{
+		{Path: "/created_by", Rule: "required", Message: "value is required"},
+		{Path: "/name", Rule: "min", Message: "length should be at least 2"},
+		{Path: "/age", Rule: "min", Message: "value should be at least 18"},
+		{Path: "/email", Rule: "email", Message: "value is not valid email"},
+		{Path: "/role", Rule: "oneof", Message: "value should be one of admin, user"},
+		{Path: "/website", Rule: "url", Message: "value is not valid URL"},
+		{Path: "/emails", Rule: "max", Message: "length should be at most 2"},
+		{Path: "/emails/1", Rule: "required", Message: "value is required"},
+		{Path: "/emails/2", Rule: "email", Message: "value is not valid email"},
+		{Path: "/addresses/1/city", Rule: "required", Message: "value is required"},
+		{Path: "/addresses/1/zip", Rule: "len", Message: "length should be 5"},
+		{Path: "/addresses/1/zip", Rule: "numeric", Message: "value is not valid numeric string"},
+		{Path: "/labels/b", Rule: "alpha", Message: "value is not valid alphabetic string"},
+	}
+	if !reflect.DeepEqual(errs, want) {
+		t.Errorf("Got:\n%v\nwant:\n%v", errs, want)
+	}
+
+	valid := validateUser{
+		validateAudit: validateAudit{CreatedBy: "admin"},
+		Name:          "John",
+		Age:           30,
+		Role:          "user",
+		Addresses:     []validateAddress{{City: "Paris", Zip: "75001"}},
+	}
+	if err := NewValidator().Validate(valid); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestValidatorRules(t *testing.T) {
+	validator := NewValidator()
+	validator.RegisterRule("even", func(value reflect.Value, param string) error {
+		if value.Int()%2 != 0 {
+			return fmt.Errorf("value should be even")
+		}
+		return nil
+	})
+
+	var value struct {
+		Count int `json:"count" validate:"even"`
+	}
+	value.Count = 3
+	want := ValidationErrors{{Path: "/count", Rule: "even", Message: "value should be even"}}
+	if err := validator.Validate(value); !reflect.DeepEqual(err, want) {
+		t.Errorf("Got - %v, want - %v", err, want)
+	}
+
+	var unknown struct {
+		Name string `validate:"unknown"`
+	}
+	if err := validator.Validate(unknown); err == nil || !strings.Contains(err.Error(), `unknown validation rule "unknown"`) {
+		t.Errorf("expected error of unknown rule, got %v", err)
+	}
+
+	var invalidParam struct {
+		Name string `validate:"min=x"`
+	}
+	invalidParam.Name = "a"
+	if err := validator.Validate(invalidParam); err == nil {
+		t.Error("expected error of invalid param")
+	}
+}
+
+func TestBindValidation(t *testing.T) {
+	router := NewRouter()
+	router.Post("/users", WithError(func(ctx *Context) error {
+		var user validateUser
+		if err := ctx.Bind(&user); err != nil {
+			return err
+		}
+		ctx.SuccessJSONResponse(user.Name)
+		return nil
+	}))
+	router.Get("/users", WithError(func(ctx *Context) error {
+		var query struct {
+			Limit int `query:"limit" validate:"max=100"`
+		}
+		return ctx.BindQuery(&query)
+	}))
+
+	w := httptest.NewRecorder()
+	body := `{"created_by":"admin","name":"John","age":30,"role":"admin","addresses":[{"city":"","zip":"12345"}]}`
+	r := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, r)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Got status - %d", w.Code)
+	}
+	var response ResponseObject[any]
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	wantDetails := []any{map[string]any{"path": "/addresses/0/city", "rule": "required", "message": "value is required"}}
+	if response.Error == nil || response.Error.Message != MessageBodyIsNotValid || !reflect.DeepEqual(response.Error.Details, wantDetails) {
+		t.Errorf("Got response - %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users?limit=500", nil))
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), `"path":"/limit"`) {
+		t.Errorf("Got - %d %s", w.Code, w.Body.String())
+	}
+}
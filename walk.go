@@ -0,0 +1,153 @@
+package rou
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"sync"
+	"text/tabwriter"
+	"unsafe"
+)
+
+// Method of mounted handlers in RouteInfo, they serve requests with any method
+const MethodAny = "*"
+
+// Description of registered route
+type RouteInfo struct {
+	Method string
+	Path   string
+	Name   string
+	// Prefix of the group in which the route is registered
+	Group string
+	// Name of the handler function, function wrapped by WithError or JSON or type of mounted http.Handler
+	Handler string
+	// Number of middlewares which wrap the handler including middlewares of router and groups
+	Middlewares int
+}
+
+// Returns full name of the function
+func functionName(fn any) string {
+	value := reflect.ValueOf(fn)
+	if value.Kind() != reflect.Func || value.IsNil() {
+		return ""
+	}
+	if function := runtime.FuncForPC(value.Pointer()); function != nil {
+		return function.Name()
+	}
+	return ""
+}
+
+// Names of functions wrapped by WithError and JSON stored by address of the wrapping closure
+var wrappedNames sync.Map
+
+type wrappedName struct {
+	// Code pointer of the wrapping closure, it is checked because address of collected closure can be reused
+	code uintptr
+	name string
+}
+
+// Records name of fn which is wrapped by handler, so Walk reports it instead of name of the wrapper
+func wrapped(handler Handler, fn any) Handler {
+	wrappedNames.Store(closureAddress(handler), wrappedName{code: reflect.ValueOf(handler).Pointer(), name: functionName(fn)})
+	return handler
+}
+
+// Returns address of the closure, it is unique for every created closure unlike its code pointer
+func closureAddress(handler Handler) uintptr {
+	return uintptr(*(*unsafe.Pointer)(unsafe.Pointer(&handler)))
+}
+
+// Returns name of function wrapped by the handler or name of the handler itself
+func handlerName(handler Handler) string {
+	if handler == nil {
+		return ""
+	}
+	if stored, ok := wrappedNames.Load(closureAddress(handler)); ok {
+		if name := stored.(wrappedName); name.code == reflect.ValueOf(handler).Pointer() {
+			return name.name
+		}
+	}
+	return functionName(handler)
+}
+
+// Calls fn for every route in order of registration including routes of mounted routers.
+//
+// Mounted handlers which are not SimpleRouter are described with method MethodAny.
+// Walking stops if fn returns an error and the error is returned.
+func (sr SimpleRouter) Walk(fn func(info RouteInfo) error) error {
+	return sr.walk("", "", len(sr.middlewares), func(info RouteInfo, route *Route) error {
+		return fn(info)
+	})
+}
+
+// Calls fn for every route with its description, route of mounted handler is given for MethodAny
+func (sr SimpleRouter) walk(prefix string, group string, middlewares int, fn func(info RouteInfo, route *Route) error) error {
+	walked := make(map[*mount]bool)
+	for _, route := range sr.Routes.list {
+		routeGroup := group
+		routeMiddlewares := middlewares + len(route.middlewares)
+		if route.group != nil {
+			routeGroup = joinPaths(prefix, route.group.prefix)
+			routeMiddlewares += len(route.group.allMiddlewares())
+		}
+
+		if route.mount == nil {
+			err := fn(RouteInfo{
+				Method:      route.method,
+				Path:        joinPaths(prefix, route.Path),
+				Name:        route.name,
+				Group:       routeGroup,
+				Handler:     handlerName(route.Handler),
+				Middlewares: routeMiddlewares,
+			}, route)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if walked[route.mount] {
+			continue
+		}
+		walked[route.mount] = true
+
+		mountPrefix := joinPaths(prefix, route.mount.prefix)
+		if subRouter, ok := route.mount.handler.(*SimpleRouter); ok {
+			err := subRouter.walk(mountPrefix, routeGroup, routeMiddlewares+len(subRouter.middlewares), fn)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		err := fn(RouteInfo{
+			Method:      MethodAny,
+			Path:        mountPrefix,
+			Group:       routeGroup,
+			Handler:     fmt.Sprintf("%T", route.mount.handler),
+			Middlewares: routeMiddlewares,
+		}, route)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Prints aligned table of all routes, it is useful for debugging on startup
+//
+//	router.PrintRoutes(os.Stdout)
+func (sr SimpleRouter) PrintRoutes(w io.Writer) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "METHOD\tPATH\tNAME\tGROUP\tHANDLER\tMIDDLEWARES")
+	err := sr.Walk(func(info RouteInfo) error {
+		_, err := fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%d\n",
+			info.Method, info.Path, info.Name, info.Group, info.Handler, info.Middlewares)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return table.Flush()
+}
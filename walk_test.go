@@ -0,0 +1,104 @@
+package rou
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func listUsersHandler(ctx *Context) {}
+
+func deleteUserHandler(ctx *Context) error {
+	return nil
+}
+
+func TestWalk(t *testing.T) {
+	router := NewRouter()
+	router.Use(middlewares[0])
+	router.Get("/users", listUsersHandler).Name("users")
+	router.Group("/api", func(api *Group) {
+		api.Use(middlewares...)
+		api.Post("/users", listUsersHandler).Middleware(middlewares[0])
+		api.Mount("/debug", http.NotFoundHandler())
+	})
+
+	subRouter := NewRouter()
+	subRouter.Group("/v1").Delete("/invoices/:id", listUsersHandler).Name("invoice")
+	router.Mount("/billing", subRouter)
+
+	var got []RouteInfo
+	err := router.Walk(func(info RouteInfo) error {
+		got = append(got, info)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	handlerName := "github.com/Moranilt/rou.listUsersHandler"
+	want := []RouteInfo{
+		{Method: MethodGet, Path: "/users", Name: "users", Handler: handlerName, Middlewares: 1},
+		{Method: MethodPost, Path: "/api/users", Group: "/api", Handler: handlerName, Middlewares: 4},
+		{Method: MethodAny, Path: "/api/debug", Group: "/api", Handler: "http.HandlerFunc", Middlewares: 3},
+		{Method: MethodDelete, Path: "/billing/v1/invoices/:id", Name: "invoice", Group: "/billing/v1", Handler: handlerName, Middlewares: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Got - %+v, want - %+v", got, want)
+	}
+
+	t.Run("stops on error", func(t *testing.T) {
+		errStop := errors.New("stop")
+		calls := 0
+		err := router.Walk(func(info RouteInfo) error {
+			calls++
+			return errStop
+		})
+		if err != errStop || calls != 1 {
+			t.Errorf("expected walking to stop, got %v after %d calls", err, calls)
+		}
+	})
+
+	t.Run("wrapped handlers", func(t *testing.T) {
+		router := NewRouter()
+		router.Delete("/users/:id", WithError(deleteUserHandler))
+		router.Get("/users/:id", JSON(typedHandler))
+		HandleJSON(router, MethodPut, "/users/:id", typedHandler)
+		router.Post("/users", listUsersHandler)
+
+		var got []string
+		router.Walk(func(info RouteInfo) error {
+			got = append(got, info.Handler)
+			return nil
+		})
+		want := []string{
+			"github.com/Moranilt/rou.deleteUserHandler",
+			"github.com/Moranilt/rou.typedHandler",
+			"github.com/Moranilt/rou.typedHandler",
+			"github.com/Moranilt/rou.listUsersHandler",
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Got - %v, want - %v", got, want)
+		}
+	})
+
+	t.Run("print routes", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := router.PrintRoutes(&buf); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 5 {
+			t.Fatalf("expected header and 4 routes, got %q", buf.String())
+		}
+		column := strings.Index(lines[0], "PATH")
+		for _, line := range lines[1:] {
+			if line[column-1] != ' ' || line[column] == ' ' {
+				t.Errorf("columns are not aligned:\n%s", buf.String())
+				break
+			}
+		}
+	})
+}